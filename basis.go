@@ -0,0 +1,377 @@
+// Basis status
+
+package clp
+
+// #include "clp-interface.h"
+import "C"
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// A BasisStatus indicates the status of a row or column with respect to a
+// simplex basis.
+type BasisStatus int
+
+// These constants are the possible values for a BasisStatus.  They match the
+// values of CLP's ClpSimplex::Status.
+const (
+	IsFree       BasisStatus = 0 // Nonbasic and free
+	Basic                    = 1 // Basic
+	AtUpperBound             = 2 // Nonbasic at its upper bound
+	AtLowerBound             = 3 // Nonbasic at its lower bound
+	SuperBasic               = 4 // Nonbasic and between its bounds
+	IsFixed                  = 5 // Nonbasic with equal lower and upper bounds
+)
+
+// String returns the name of a BasisStatus.
+func (bs BasisStatus) String() string {
+	switch bs {
+	case IsFree:
+		return "IsFree"
+	case Basic:
+		return "Basic"
+	case AtUpperBound:
+		return "AtUpperBound"
+	case AtLowerBound:
+		return "AtLowerBound"
+	case SuperBasic:
+		return "SuperBasic"
+	case IsFixed:
+		return "IsFixed"
+	default:
+		return fmt.Sprintf("BasisStatus(%d)", int(bs))
+	}
+}
+
+// A Basis records the basis status of every column and row in a model.  It
+// can be retrieved from one simplex model and installed in another with the
+// same dimensions to warm-start a solve.
+type Basis struct {
+	Columns []BasisStatus // Status of each column
+	Rows    []BasisStatus // Status of each row
+}
+
+// ColumnStatus returns the basis status of a column.  If no basis exists yet,
+// an all-slack basis is created first.  ColumnStatus returns an error if the
+// column number is out of range.
+func (s *Simplex) ColumnStatus(col int) (BasisStatus, error) {
+	if err := s.checkColumn("Simplex.ColumnStatus", col); err != nil {
+		return 0, err
+	}
+	var cErr C.clp_error
+	st := C.simplex_get_col_status(s.model, C.int(col), &cErr)
+	return BasisStatus(st), cError("Simplex.ColumnStatus", &cErr)
+}
+
+// RowStatus returns the basis status of a row.  If no basis exists yet, an
+// all-slack basis is created first.  RowStatus returns an error if the row
+// number is out of range.
+func (s *Simplex) RowStatus(row int) (BasisStatus, error) {
+	if err := s.checkRow("Simplex.RowStatus", row); err != nil {
+		return 0, err
+	}
+	var cErr C.clp_error
+	st := C.simplex_get_row_status(s.model, C.int(row), &cErr)
+	return BasisStatus(st), cError("Simplex.RowStatus", &cErr)
+}
+
+// SetColumnStatus sets the basis status of a column.  SetColumnStatus returns
+// an error if the column number is out of range or the status is invalid.
+func (s *Simplex) SetColumnStatus(col int, st BasisStatus) error {
+	if err := s.checkColumn("Simplex.SetColumnStatus", col); err != nil {
+		return err
+	}
+	if st < IsFree || st > IsFixed {
+		return fmt.Errorf("%w: Simplex.SetColumnStatus given invalid status %d for %s", ErrInvalidArgument, int(st), s.columnLabel(col))
+	}
+	var cErr C.clp_error
+	C.simplex_set_col_status(s.model, C.int(col), C.int(st), &cErr)
+	return cError("Simplex.SetColumnStatus", &cErr)
+}
+
+// SetRowStatus sets the basis status of a row.  SetRowStatus returns an error
+// if the row number is out of range or the status is invalid.
+func (s *Simplex) SetRowStatus(row int, st BasisStatus) error {
+	if err := s.checkRow("Simplex.SetRowStatus", row); err != nil {
+		return err
+	}
+	if st < IsFree || st > IsFixed {
+		return fmt.Errorf("%w: Simplex.SetRowStatus given invalid status %d for %s", ErrInvalidArgument, int(st), s.rowLabel(row))
+	}
+	var cErr C.clp_error
+	C.simplex_set_row_status(s.model, C.int(row), C.int(st), &cErr)
+	return cError("Simplex.SetRowStatus", &cErr)
+}
+
+// Basis returns the basis status of every column and row.  If no basis
+// exists yet, an all-slack basis is created first.
+func (s *Simplex) Basis() Basis {
+	nr, nc := s.Dims()
+	cCols := cMalloc(nc+1, C.int(0))
+	defer cFree(cCols)
+	cRows := cMalloc(nr+1, C.int(0))
+	defer cFree(cRows)
+	var cErr C.clp_error
+	C.simplex_get_basis(s.model, (*C.int)(cCols), (*C.int)(cRows), &cErr)
+	if s.recordErr(cError("Simplex.Basis", &cErr)) {
+		return Basis{}
+	}
+	b := Basis{
+		Columns: make([]BasisStatus, nc),
+		Rows:    make([]BasisStatus, nr),
+	}
+	for i, st := range cIntSlice(cCols, nc) {
+		b.Columns[i] = BasisStatus(st)
+	}
+	for i, st := range cIntSlice(cRows, nr) {
+		b.Rows[i] = BasisStatus(st)
+	}
+	return b
+}
+
+// SetBasis installs a basis, typically one returned by Basis, possibly from
+// a different simplex model.  A subsequent Primal or Dual solve starts from
+// this basis.  SetBasis returns an error if the basis does not match the
+// model's dimensions or contains an invalid status.  It does not check that
+// the basis is nonsingular; CLP repairs bad bases during factorization.
+func (s *Simplex) SetBasis(b Basis) error {
+	nr, nc := s.Dims()
+	if len(b.Columns) != nc || len(b.Rows) != nr {
+		return fmt.Errorf("%w: Simplex.SetBasis given a basis for %d rows and %d columns but the model has %d rows and %d columns",
+			ErrDimensionMismatch, len(b.Rows), len(b.Columns), nr, nc)
+	}
+	cCols, err := cBasisStatuses(b.Columns, s.columnLabel)
+	if err != nil {
+		return fmt.Errorf("%w: Simplex.SetBasis %v", ErrInvalidArgument, err)
+	}
+	defer cFree(cCols)
+	cRows, err := cBasisStatuses(b.Rows, s.rowLabel)
+	if err != nil {
+		return fmt.Errorf("%w: Simplex.SetBasis %v", ErrInvalidArgument, err)
+	}
+	defer cFree(cRows)
+	var cErr C.clp_error
+	C.simplex_set_basis(s.model, (*C.int)(cCols), (*C.int)(cRows), &cErr)
+	return cError("Simplex.SetBasis", &cErr)
+}
+
+// cBasisStatuses converts a list of basis statuses to a C vector.  It returns
+// an error, describing the offending row or column with label, if any status
+// is invalid.  On success, the caller must free the vector with cFree.
+func cBasisStatuses(sts []BasisStatus, label func(int) string) (unsafe.Pointer, error) {
+	cs := cMalloc(len(sts)+1, C.int(0))
+	is := cIntSlice(cs, len(sts))
+	for i, st := range sts {
+		if st < IsFree || st > IsFixed {
+			cFree(cs)
+			return nil, fmt.Errorf("%s has invalid status %d", label(i), int(st))
+		}
+		is[i] = C.int(st)
+	}
+	return cs, nil
+}
+
+// WriteBasis writes the model's current basis in MPS basis format, as used by
+// CLP's writeBasis and readBasis methods.  Each basic column is paired with a
+// nonbasic row and written as an XU or XL record according to whether the
+// row is at its upper or lower bound.  Nonbasic columns at their upper bound
+// are written as UL records.  All other nonbasic columns are omitted and are
+// assumed to lie at a finite bound when the file is read back.  Rows and
+// columns are identified by name, with CLP making up names for those that
+// were not explicitly assigned one.  As an extension to CLP's format,
+// WriteBasis follows the NAME line with a comment recording the model's
+// dimensions, which ReadBasis checks and CLP ignores.  WriteBasis returns an
+// error if the basis has more basic columns than nonbasic rows or if writing
+// fails.
+func (s *Simplex) WriteBasis(w io.Writer) error {
+	b := s.Basis()
+	rowNames := s.RowNames()
+	colNames := s.ColumnNames()
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, strings.TrimSpace("NAME          "+s.problemName()))
+	fmt.Fprintf(bw, "* Rows %d Cols %d\n", len(b.Rows), len(b.Columns))
+	r := 0
+	for c, st := range b.Columns {
+		switch st {
+		case Basic:
+			for r < len(b.Rows) && b.Rows[r] == Basic {
+				r++
+			}
+			if r == len(b.Rows) {
+				return fmt.Errorf("%w: Simplex.WriteBasis found more basic columns than nonbasic rows", ErrInvalidArgument)
+			}
+			code := "XL"
+			if b.Rows[r] == AtUpperBound {
+				code = "XU"
+			}
+			fmt.Fprintf(bw, " %s %-8s  %s\n", code, colNames[c], rowNames[r])
+			r++
+		case AtUpperBound:
+			fmt.Fprintf(bw, " UL %s\n", colNames[c])
+		}
+	}
+	fmt.Fprintln(bw, "ENDATA")
+	return bw.Flush()
+}
+
+// nonbasicStatus returns the status CLP would assign to a nonbasic variable
+// with the given bounds: fixed if the bounds are equal, otherwise at a finite
+// bound, preferring the lower, or free if neither bound is finite.
+func nonbasicStatus(b Bounds) BasisStatus {
+	switch {
+	case b.Lower == b.Upper:
+		return IsFixed
+	case !math.IsInf(b.Lower, -1):
+		return AtLowerBound
+	case !math.IsInf(b.Upper, 1):
+		return AtUpperBound
+	default:
+		return IsFree
+	}
+}
+
+// problemName returns the model's name, which ReadMPS takes from the MPS
+// file's NAME line.  If CLP throws an exception, problemName returns the empty
+// string, and Err reports the exception.
+func (s *Simplex) problemName() string {
+	var cErr C.clp_error
+	cName := C.simplex_get_problem_name(s.model, &cErr)
+	if s.recordErr(cError("Simplex.problemName", &cErr)) {
+		return ""
+	}
+	defer cFree(unsafe.Pointer(cName))
+	return C.GoString(cName)
+}
+
+// basisDims extracts the dimensions that WriteBasis records in a basis
+// file's comment line as "Rows nr Cols nc".  This is our own extension;
+// CLP's writeBasis does not record the dimensions.  basisDims returns false
+// if the fields do not record both dimensions.
+func basisDims(fields []string) (nr, nc int, ok bool) {
+	var sawRows, sawCols bool
+	for i := 0; i+1 < len(fields); i++ {
+		n, err := strconv.Atoi(fields[i+1])
+		if err != nil {
+			continue
+		}
+		switch fields[i] {
+		case "Rows":
+			nr, sawRows = n, true
+		case "Cols":
+			nc, sawCols = n, true
+		}
+	}
+	return nr, nc, sawRows && sawCols
+}
+
+// ReadBasis reads a basis in the MPS basis format produced by WriteBasis and
+// by CLP's writeBasis method and installs it in the model.  Rows and columns
+// are identified by name.  ReadBasis returns an error wrapping
+// ErrInvalidArgument if the file is malformed, ErrIndexOutOfRange if it
+// refers to a row or column the model does not contain, or
+// ErrDimensionMismatch if it was written by WriteBasis for a model of
+// different dimensions.  A file written by CLP does not record the model's
+// dimensions, so it is checked only by name.  On error, the model's basis is
+// left unchanged.
+func (s *Simplex) ReadBasis(r io.Reader) error {
+	// Start with all columns nonbasic and all rows basic.
+	cb := s.ColumnBounds()
+	b := Basis{
+		Columns: make([]BasisStatus, len(cb)),
+		Rows:    make([]BasisStatus, len(s.RowBounds())),
+	}
+	for c, bnd := range cb {
+		b.Columns[c] = nonbasicStatus(bnd)
+	}
+	for i := range b.Rows {
+		b.Rows[i] = Basic
+	}
+	rowMap := s.RowNameMap()
+	colMap := s.ColumnNameMap()
+	seenCol := make(map[int]bool, len(cb))
+	seenRow := make(map[int]bool, len(b.Rows))
+
+	// Process the file line by line.
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	sawName := false
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		if strings.HasPrefix(line, "*") {
+			if nr, nc, ok := basisDims(fields); ok && (nr != len(b.Rows) || nc != len(b.Columns)) {
+				return fmt.Errorf("%w: Simplex.ReadBasis line %d: basis is for %d rows and %d columns but the model has %d rows and %d columns",
+					ErrDimensionMismatch, lineNum, nr, nc, len(b.Rows), len(b.Columns))
+			}
+			continue
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		errorf := func(sentinel error, format string, a ...interface{}) error {
+			return fmt.Errorf("%w: Simplex.ReadBasis line %d: %s", sentinel, lineNum, fmt.Sprintf(format, a...))
+		}
+		if !sawName {
+			if fields[0] != "NAME" {
+				return errorf(ErrInvalidArgument, "expected NAME but saw %q", fields[0])
+			}
+			sawName = true
+			continue
+		}
+		if fields[0] == "ENDATA" {
+			return s.SetBasis(b)
+		}
+
+		// Look up the column and, for XU and XL, the row.
+		code := fields[0]
+		want := 2
+		if code == "XU" || code == "XL" {
+			want = 3
+		}
+		if len(fields) != want {
+			return errorf(ErrInvalidArgument, "expected %d fields but saw %d", want, len(fields))
+		}
+		c, ok := colMap[fields[1]]
+		if !ok {
+			return errorf(ErrIndexOutOfRange, "unknown column %q", fields[1])
+		}
+		if seenCol[c] {
+			return errorf(ErrInvalidArgument, "duplicate column %q", fields[1])
+		}
+		seenCol[c] = true
+		switch code {
+		case "XU", "XL":
+			row, ok := rowMap[fields[2]]
+			if !ok {
+				return errorf(ErrIndexOutOfRange, "unknown row %q", fields[2])
+			}
+			if seenRow[row] {
+				return errorf(ErrInvalidArgument, "duplicate row %q", fields[2])
+			}
+			seenRow[row] = true
+			b.Columns[c] = Basic
+			if code == "XU" {
+				b.Rows[row] = AtUpperBound
+			} else {
+				b.Rows[row] = AtLowerBound
+			}
+		case "UL":
+			b.Columns[c] = AtUpperBound
+		case "LL":
+			b.Columns[c] = AtLowerBound
+		default:
+			return errorf(ErrInvalidArgument, "unknown record type %q", code)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: Simplex.ReadBasis line %d: missing ENDATA", ErrInvalidArgument, lineNum)
+}
@@ -0,0 +1,197 @@
+// Test basis-status manipulation
+
+package clp_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lanl/clp"
+)
+
+// Test if we can get and set the status of individual rows and columns.
+func TestRowColumnStatus(t *testing.T) {
+	simp := smallProblem()
+
+	// At the optimum of smallProblem, both columns are basic, and both
+	// rows are nonbasic.
+	for c := 0; c < 2; c++ {
+		if st, err := simp.ColumnStatus(c); err != nil || st != clp.Basic {
+			t.Fatalf("Expected column %d to be Basic but saw %v (%v)", c, st, err)
+		}
+	}
+	if st, err := simp.RowStatus(0); err != nil || (st != clp.AtLowerBound && st != clp.AtUpperBound) {
+		t.Fatalf("Expected row 0 to be nonbasic but saw %v (%v)", st, err)
+	}
+
+	// Ensure that we can change individual statuses.
+	if err := simp.SetColumnStatus(1, clp.AtLowerBound); err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := simp.ColumnStatus(1); st != clp.AtLowerBound {
+		t.Fatalf("Expected column 1 to be AtLowerBound but saw %v", st)
+	}
+	if err := simp.SetRowStatus(0, clp.Basic); err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := simp.RowStatus(0); st != clp.Basic {
+		t.Fatalf("Expected row 0 to be Basic but saw %v", st)
+	}
+
+	// Ensure that bad indices and statuses are rejected.
+	if _, err := simp.ColumnStatus(2); !errors.Is(err, clp.ErrIndexOutOfRange) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIndexOutOfRange, err)
+	}
+	if _, err := simp.RowStatus(-1); !errors.Is(err, clp.ErrIndexOutOfRange) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIndexOutOfRange, err)
+	}
+	if err := simp.SetColumnStatus(5, clp.Basic); !errors.Is(err, clp.ErrIndexOutOfRange) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIndexOutOfRange, err)
+	}
+	if err := simp.SetRowStatus(0, 17); !errors.Is(err, clp.ErrInvalidArgument) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrInvalidArgument, err)
+	}
+
+	// A model that has never been solved has an all-slack basis.
+	fresh := clp.NewSimplex()
+	fresh.LoadProblem(simp.Matrix(), nil, simp.Objective(), simp.RowBounds(), nil)
+	for r := 0; r < 2; r++ {
+		if st, _ := fresh.RowStatus(r); st != clp.Basic {
+			t.Fatalf("Expected row %d of an unsolved model to be Basic but saw %v", r, st)
+		}
+	}
+}
+
+// Test if we can carry a basis from one simplex model to another.
+func TestBasisWarmStart(t *testing.T) {
+	simp := smallProblem()
+	b := simp.Basis()
+	if len(b.Columns) != 2 || len(b.Rows) != 2 {
+		t.Fatalf("Expected a basis for 2 rows and 2 columns but saw %v", b)
+	}
+	nBasic := 0
+	for _, st := range append(b.Columns, b.Rows...) {
+		if st == clp.Basic {
+			nBasic++
+		}
+	}
+	if nBasic != 2 {
+		t.Fatalf("Expected 2 basic variables but saw %d in %v", nBasic, b)
+	}
+
+	// Install the basis in a fresh model with the same data, and ensure
+	// that it survives the trip and leads to the same optimum.
+	fresh := clp.NewSimplex()
+	fresh.LoadProblem(simp.Matrix(), nil, simp.Objective(), simp.RowBounds(), nil)
+	if err := fresh.SetBasis(b); err != nil {
+		t.Fatal(err)
+	}
+	b2 := fresh.Basis()
+	for i, st := range b.Columns {
+		if b2.Columns[i] != st {
+			t.Fatalf("Expected column statuses %v but saw %v", b.Columns, b2.Columns)
+		}
+	}
+	for i, st := range b.Rows {
+		if b2.Rows[i] != st {
+			t.Fatalf("Expected row statuses %v but saw %v", b.Rows, b2.Rows)
+		}
+	}
+	fresh.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+	soln := fresh.PrimalColumnSolution()
+	if !closeTo(soln[0], 1.75, 0.005) || !closeTo(soln[1], 2.25, 0.005) {
+		t.Fatalf("Expected [1.75 2.25] but observed %v", soln)
+	}
+
+	// Ensure that mismatched and invalid bases are rejected.
+	if err := fresh.SetBasis(clp.Basis{Columns: b.Columns}); err == nil {
+		t.Fatal("Expected an error when setting a basis with no rows")
+	}
+	bad := clp.Basis{
+		Columns: []clp.BasisStatus{clp.Basic, 17},
+		Rows:    []clp.BasisStatus{clp.Basic, clp.AtLowerBound},
+	}
+	if err := fresh.SetBasis(bad); err == nil {
+		t.Fatal("Expected an error when setting a basis with an invalid status")
+	}
+}
+
+// Test if we can write a basis file and read it back.
+func TestBasisFile(t *testing.T) {
+	// Write the optimal basis.
+	simp := smallProblem()
+	var buf bytes.Buffer
+	if err := simp.WriteBasis(&buf); err != nil {
+		t.Fatal(err)
+	}
+	text := buf.String()
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if len(lines) != 5 || lines[0] != "NAME" || lines[1] != "* Rows 2 Cols 2" || lines[4] != "ENDATA" {
+		t.Fatalf("Unexpected basis file:\n%s", text)
+	}
+	for _, ln := range lines[2:4] {
+		if f := strings.Fields(ln); len(f) != 3 || (f[0] != "XU" && f[0] != "XL") {
+			t.Fatalf("Expected an XU or XL record but saw %q", ln)
+		}
+	}
+
+	// Read the basis into a fresh model and ensure it matches.
+	fresh := clp.NewSimplex()
+	fresh.LoadProblem(simp.Matrix(), nil, simp.Objective(), simp.RowBounds(), nil)
+	if err := fresh.ReadBasis(strings.NewReader(text)); err != nil {
+		t.Fatal(err)
+	}
+	b1, b2 := simp.Basis(), fresh.Basis()
+	for i, st := range b1.Columns {
+		if b2.Columns[i] != st {
+			t.Fatalf("Expected column statuses %v but saw %v", b1.Columns, b2.Columns)
+		}
+	}
+	for i, st := range b1.Rows {
+		if b2.Rows[i] != st {
+			t.Fatalf("Expected row statuses %v but saw %v", b1.Rows, b2.Rows)
+		}
+	}
+
+	// A file without the dimensions comment, as CLP writes, should also be
+	// accepted.
+	clpText := strings.Replace(text, lines[1]+"\n", "", 1)
+	if err := fresh.ReadBasis(strings.NewReader(clpText)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A model with different column names should reject the basis.
+	named := clp.NewSimplex()
+	named.LoadProblem(simp.Matrix(), nil, simp.Objective(), simp.RowBounds(), nil)
+	if err := named.SetColumnNames([]string{"x", "y"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := named.ReadBasis(strings.NewReader(text)); !errors.Is(err, clp.ErrIndexOutOfRange) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIndexOutOfRange, err)
+	}
+
+	// A larger model whose made-up names include all of those in the file
+	// should also reject the basis.
+	larger := clp.NewSimplex()
+	larger.LoadProblem(simp.Matrix(), nil, simp.Objective(), simp.RowBounds(), nil)
+	if err := larger.AddColumns(nil, nil, [][]clp.Nonzero{{{Index: 0, Value: 1}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := larger.ReadBasis(strings.NewReader(text)); !errors.Is(err, clp.ErrDimensionMismatch) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrDimensionMismatch, err)
+	}
+
+	// Malformed files should be rejected.
+	for _, bad := range []string{
+		"",
+		"NAME\n",
+		"NAME\n XX C0000000\nENDATA\n",
+		"NAME\n UL C0000000\n UL C0000000\nENDATA\n",
+	} {
+		if err := fresh.ReadBasis(strings.NewReader(bad)); !errors.Is(err, clp.ErrInvalidArgument) {
+			t.Fatalf("Expected %v when reading %q but saw %v", clp.ErrInvalidArgument, bad, err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Solver event callbacks
+
+package clp
+
+// #include "clp-interface.h"
+import "C"
+import (
+	"fmt"
+	"sync"
+)
+
+// An Event indicates the point in a solve at which an EventHandler is
+// invoked.  The values match those of CLP's ClpEventHandler::Event.
+type Event int
+
+// These constants are the events that are passed to an EventHandler.
+const (
+	EndOfIteration     Event = 100 // End of a simplex iteration
+	EndOfFactorization       = 101 // End of a refactorization of the basis
+	EndOfValuesPass          = 102 // End of a values pass
+)
+
+// String returns the name of an Event.
+func (e Event) String() string {
+	switch e {
+	case EndOfIteration:
+		return "EndOfIteration"
+	case EndOfFactorization:
+		return "EndOfFactorization"
+	case EndOfValuesPass:
+		return "EndOfValuesPass"
+	default:
+		return fmt.Sprintf("Event(%d)", int(e))
+	}
+}
+
+// Progress reports the state of a solve to an EventHandler.
+type Progress struct {
+	Event               Event   // Point in the solve at which the handler was invoked
+	Iteration           int     // Number of iterations performed so far
+	Objective           float64 // Current value of the objective function
+	PrimalInfeasibility float64 // Sum of primal infeasibilities
+	DualInfeasibility   float64 // Sum of dual infeasibilities
+}
+
+// An EventHandler is invoked repeatedly during a solve.  It returns true to
+// stop the solve, in which case the solve returns StoppedByEventHandler, or
+// false to let it continue.  An EventHandler runs on the goroutine that
+// called the solve method and must not panic.
+type EventHandler func(p Progress) (stop bool)
+
+// handles maps the opaque handles that are passed to C to the corresponding
+// Go callbacks.  We store the callbacks rather than the Simplex so as not to
+// prevent the Simplex from being garbage-collected.
+var handles = struct {
+	sync.Mutex
+	next    uintptr
+	entries map[uintptr]interface{}
+}{entries: make(map[uintptr]interface{})}
+
+// registerHandle associates a new, nonzero handle with a Go value and returns
+// the handle.
+func registerHandle(v interface{}) uintptr {
+	handles.Lock()
+	defer handles.Unlock()
+	handles.next++
+	handles.entries[handles.next] = v
+	return handles.next
+}
+
+// unregisterHandle forgets a handle returned by registerHandle.  Handle 0 is
+// ignored.
+func unregisterHandle(h uintptr) {
+	if h == 0 {
+		return
+	}
+	handles.Lock()
+	delete(handles.entries, h)
+	handles.Unlock()
+}
+
+// lookupHandle returns the Go value associated with a handle or nil if there
+// is none.
+func lookupHandle(h uintptr) interface{} {
+	handles.Lock()
+	defer handles.Unlock()
+	return handles.entries[h]
+}
+
+//export goEventCallback
+func goEventCallback(handle C.uintptr_t, event C.int, iter C.int, obj, primInf, dualInf C.double) C.int {
+	h, ok := lookupHandle(uintptr(handle)).(EventHandler)
+	if !ok {
+		return 0
+	}
+	stop := h(Progress{
+		Event:               Event(event),
+		Iteration:           int(iter),
+		Objective:           float64(obj),
+		PrimalInfeasibility: float64(primInf),
+		DualInfeasibility:   float64(dualInf),
+	})
+	if stop {
+		return 1
+	}
+	return 0
+}
+
+// SetEventHandler installs a function that is invoked at the end of every
+// iteration and refactorization of subsequent Primal and Dual solves (and of
+// the crossover phase of Barrier).  The function can report progress or stop
+// the solve.  Passing nil removes any existing handler.  The package retains
+// the handler on CLP's behalf, so a handler that refers to its own Simplex
+// keeps the Simplex from being garbage-collected until the handler is
+// removed.
+func (s *Simplex) SetEventHandler(h EventHandler) {
+	old := s.eventHandle
+	s.eventHandler = h
+	s.eventHandle = 0
+	if h != nil {
+		s.eventHandle = registerHandle(h)
+	}
+	var cErr C.clp_error
+	C.simplex_set_event_handler(s.model, C.uintptr_t(s.eventHandle), &cErr)
+	s.recordErr(cError("Simplex.SetEventHandler", &cErr))
+	unregisterHandle(old)
+}
+
+// EventHandler returns the function installed by SetEventHandler or nil if
+// there is none.
+func (s *Simplex) EventHandler() EventHandler {
+	return s.eventHandler
+}
@@ -0,0 +1,59 @@
+// Test solver event callbacks
+
+package clp_test
+
+import (
+	"testing"
+
+	"github.com/lanl/clp"
+)
+
+// Test if an event handler observes the progress of a solve.
+func TestEventHandlerProgress(t *testing.T) {
+	simp := transportProblem()
+	nIters := 0
+	lastIter := -1
+	simp.SetEventHandler(func(p clp.Progress) bool {
+		if p.Event == clp.EndOfIteration {
+			nIters++
+			if p.Iteration < lastIter {
+				t.Errorf("Iteration number decreased from %d to %d", lastIter, p.Iteration)
+			}
+			lastIter = p.Iteration
+		}
+		return false
+	})
+	if st := simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions); st != clp.Optimal {
+		t.Fatalf("Expected status %d but saw %d", clp.Optimal, st)
+	}
+	if nIters == 0 {
+		t.Fatal("Event handler was never invoked at the end of an iteration")
+	}
+	if nIters > simp.NumberIterations() {
+		t.Fatalf("Event handler observed %d iterations but the solve reported only %d", nIters, simp.NumberIterations())
+	}
+}
+
+// Test if an event handler can stop a solve.
+func TestEventHandlerStop(t *testing.T) {
+	// Stop the solve after a couple of iterations.
+	simp := transportProblem()
+	simp.SetEventHandler(func(p clp.Progress) bool {
+		return p.Event == clp.EndOfIteration && p.Iteration >= 2
+	})
+	if st := simp.Dual(clp.NoValuesPass, clp.NoStartFinishOptions); st != clp.StoppedByEventHandler {
+		t.Fatalf("Expected status %d but saw %d", clp.StoppedByEventHandler, st)
+	}
+	if n := simp.NumberIterations(); n > 3 {
+		t.Fatalf("Expected the solve to stop after 2 iterations but it ran %d", n)
+	}
+
+	// Ensure that the model is still usable once the handler is removed.
+	simp.SetEventHandler(nil)
+	if simp.EventHandler() != nil {
+		t.Fatal("Expected no event handler after removing it")
+	}
+	if st := simp.Dual(clp.NoValuesPass, clp.NoStartFinishOptions); st != clp.Optimal {
+		t.Fatalf("Expected status %d but saw %d", clp.Optimal, st)
+	}
+}
@@ -1,67 +1,248 @@
+#include <ClpDualRowDantzig.hpp>
+#include <ClpDualRowSteepest.hpp>
+#include <ClpEventHandler.hpp>
+#include <ClpPackedMatrix.hpp>
+#include <ClpPresolve.hpp>
+#include <ClpPrimalColumnDantzig.hpp>
+#include <ClpPrimalColumnSteepest.hpp>
 #include <ClpSimplex.hpp>
+#include <ClpSolve.hpp>
+#include <CoinError.hpp>
+#include <CoinMessageHandler.hpp>
+#include <algorithm>
+#include <cstdio>
+#include <cstring>
+#include <exception>
+#include <new>
+#include <string>
+#include <vector>
 #include "clp-interface.h"
 
+// Record an exception in a clp_error.  The message is copied to malloc'ed
+// memory, which the Go caller frees.  If even that allocation fails, the
+// message is left NULL, and Go relies on the code alone.
+static void set_error (clp_error* err, int code, const std::string& message)
+{
+  err->code = code;
+  err->message = strdup(message.c_str());
+}
+
+// CATCH_ERRORS follows the try block that makes up the body of every wrapper
+// function.  It records any exception in err.  An exception that escaped
+// through cgo would abort the entire Go process.
+#define CATCH_ERRORS(err)                                               \
+  catch (CoinError& e) {                                                \
+    set_error(err, CLP_COIN_ERROR,                                      \
+              e.className() + "::" + e.methodName() + ": " + e.message()); \
+  }                                                                     \
+  catch (std::bad_alloc& e) {                                           \
+    set_error(err, CLP_BAD_ALLOC, e.what());                            \
+  }                                                                     \
+  catch (std::exception& e) {                                           \
+    set_error(err, CLP_STD_EXCEPTION, e.what());                        \
+  }                                                                     \
+  catch (...) {                                                         \
+    set_error(err, CLP_UNKNOWN_EXCEPTION, "unknown exception");         \
+  }
+
+// goEventCallback is implemented in Go (callback.go).  It returns nonzero to
+// stop the solve.
+extern "C" int goEventCallback(uintptr_t handle, int event, int iteration,
+                               double objective, double primal_inf,
+                               double dual_inf);
+
+// goLogCallback is implemented in Go (log.go).  It receives a single,
+// formatted log message.
+extern "C" void goLogCallback(uintptr_t handle, int number, char severity,
+                              const char* source, const char* text);
+
+// A GoEventHandler forwards CLP events to a Go callback, identified by an
+// opaque handle.
+class GoEventHandler : public ClpEventHandler {
+public:
+  GoEventHandler(uintptr_t handle) : ClpEventHandler(), handle_(handle) {}
+  GoEventHandler(const GoEventHandler& rhs) : ClpEventHandler(rhs), handle_(rhs.handle_) {}
+  virtual ~GoEventHandler() {}
+
+  virtual ClpEventHandler* clone() const
+  {
+    return new GoEventHandler(*this);
+  }
+
+  // Forward progress events to Go.  Return -1 to continue or a
+  // nonnegative value to stop.  Other events are ignored because CLP
+  // assigns their return values different meanings.
+  virtual int event(Event whichEvent)
+  {
+    switch (whichEvent) {
+    case endOfIteration:
+    case endOfFactorization:
+    case endOfValuesPass:
+      break;
+    default:
+      return -1;
+    }
+    ClpSimplex* clp = model_;
+    if (clp == NULL)
+      return -1;
+    int stop = goEventCallback(handle_, int(whichEvent),
+                               clp->numberIterations(),
+                               clp->objectiveValue(),
+                               clp->sumPrimalInfeasibilities(),
+                               clp->sumDualInfeasibilities());
+    return stop ? 0 : -1;
+  }
+
+private:
+  uintptr_t handle_;
+};
+
+// A GoMessageHandler forwards CLP log messages to a Go callback, identified
+// by an opaque handle, instead of writing them to standard output.
+class GoMessageHandler : public CoinMessageHandler {
+public:
+  GoMessageHandler(uintptr_t handle) : CoinMessageHandler(), handle_(handle) {}
+  GoMessageHandler(const GoMessageHandler& rhs) : CoinMessageHandler(rhs), handle_(rhs.handle_) {}
+  virtual ~GoMessageHandler() {}
+
+  virtual CoinMessageHandler* clone() const
+  {
+    return new GoMessageHandler(*this);
+  }
+
+  // Pass the completed message to Go.
+  virtual int print()
+  {
+    goLogCallback(handle_, currentMessage().externalNumber(),
+                  currentMessage().severity(), currentSource().c_str(),
+                  messageBuffer());
+    return 0;
+  }
+
+private:
+  uintptr_t handle_;
+};
+
 extern "C" {
 
   // Create a new CoinPackedMatrix.
-  clp_object* new_packed_matrix (void)
+  clp_object* new_packed_matrix (clp_error* err)
   {
-    CoinPackedMatrix* matrix = new CoinPackedMatrix();
-    return (clp_object*)matrix;
+    try {
+      CoinPackedMatrix* matrix = new CoinPackedMatrix();
+      return (clp_object*)matrix;
+    }
+    CATCH_ERRORS(err)
+    return NULL;
   }
 
   void reserve (clp_object* matrix,
                 int newMaxMajorDim,
                 int newMaxSize,
-                int create)
+                int create,
+                clp_error* err)
   {
-    ((CoinPackedMatrix*)matrix)->reserve(newMaxMajorDim, (CoinBigIndex)newMaxSize, bool(create));
+    try {
+      ((CoinPackedMatrix*)matrix)->reserve(newMaxMajorDim, (CoinBigIndex)newMaxSize, bool(create));
+    }
+    CATCH_ERRORS(err)
   }
 
   void set_dimensions (clp_object* matrix,
                 int numrows,
-                int numcols)
+                int numcols,
+                clp_error* err)
   {
-    ((CoinPackedMatrix*)matrix)->setDimensions(numrows, numcols);
+    try {
+      ((CoinPackedMatrix*)matrix)->setDimensions(numrows, numcols);
+    }
+    CATCH_ERRORS(err)
   }
 
   // Free an existing CoinPackedMatrix.
-  void free_packed_matrix (clp_object* matrix)
+  void free_packed_matrix (clp_object* matrix, clp_error* err)
   {
-    delete (CoinPackedMatrix*)matrix;
+    try {
+      delete (CoinPackedMatrix*)matrix;
+    }
+    CATCH_ERRORS(err)
   }
 
   // Append a (sparse) column to a CoinPackedMatrix.
   void pm_append_col (clp_object* matrix, const int vecsize,
-                      const int* vecind, const double* vecelem)
+                      const int* vecind, const double* vecelem, clp_error* err)
   {
-    ((CoinPackedMatrix*)matrix)->appendCol(vecsize, vecind, vecelem);
+    try {
+      ((CoinPackedMatrix*)matrix)->appendCol(vecsize, vecind, vecelem);
+    }
+    CATCH_ERRORS(err)
   }
 
   // Append a (sparse) row to a CoinPackedMatrix.
   void pm_append_row (clp_object* matrix, const int vecsize,
-                      const int* vecind, const double* vecelem)
+                      const int* vecind, const double* vecelem, clp_error* err)
   {
-    ((CoinPackedMatrix*)matrix)->appendRow(vecsize, vecind, vecelem);
+    try {
+      ((CoinPackedMatrix*)matrix)->appendRow(vecsize, vecind, vecelem);
+    }
+    CATCH_ERRORS(err)
   }
 
   // Delete a number of columns from a CoinPackedMatrix.
-  void pm_delete_cols (clp_object* matrix, const int ncols, const int* columns)
+  void pm_delete_cols (clp_object* matrix, const int ncols, const int* columns, clp_error* err)
   {
-    ((CoinPackedMatrix*)matrix)->deleteCols(ncols, columns);
+    try {
+      ((CoinPackedMatrix*)matrix)->deleteCols(ncols, columns);
+    }
+    CATCH_ERRORS(err)
   }
 
   // Delete a number of rows from a CoinPackedMatrix.
-  void pm_delete_rows (clp_object* matrix, const int nrows, const int* rows)
+  void pm_delete_rows (clp_object* matrix, const int nrows, const int* rows, clp_error* err)
   {
-    ((CoinPackedMatrix*)matrix)->deleteRows(nrows, rows);
+    try {
+      ((CoinPackedMatrix*)matrix)->deleteRows(nrows, rows);
+    }
+    CATCH_ERRORS(err)
   }
 
   // Retrieve a CoinPackedMatrix's rows and columns.
-  void pm_get_dims (clp_object* matrix, int* nrows, int* ncols)
+  void pm_get_dims (clp_object* matrix, int* nrows, int* ncols, clp_error* err)
+  {
+    try {
+      *nrows = ((CoinPackedMatrix*)matrix)->getNumRows();
+      *ncols = ((CoinPackedMatrix*)matrix)->getNumCols();
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Say whether a CoinPackedMatrix is column-ordered.
+  int pm_is_col_ordered (clp_object* matrix, clp_error* err)
   {
-    *nrows = ((CoinPackedMatrix*)matrix)->getNumRows();
-    *ncols = ((CoinPackedMatrix*)matrix)->getNumCols();
+    try {
+      return int(((CoinPackedMatrix*)matrix)->isColOrdered());
+    }
+    CATCH_ERRORS(err)
+    return 0;
+  }
+
+  // Switch a CoinPackedMatrix between column and row ordering without
+  // changing its contents.
+  void pm_reverse_ordering (clp_object* matrix, clp_error* err)
+  {
+    try {
+      ((CoinPackedMatrix*)matrix)->reverseOrdering();
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Transpose a CoinPackedMatrix in place.
+  void pm_transpose (clp_object* matrix, clp_error* err)
+  {
+    try {
+      ((CoinPackedMatrix*)matrix)->transpose();
+    }
+    CATCH_ERRORS(err)
   }
 
   // Retrieve a CoinPackedMatrix's data in a sparse representation.
@@ -69,173 +250,993 @@ extern "C" {
                            const int** starts,
                            const int** lengths,
                            const int** indices,
-                           const double** elements)
+                           const double** elements,
+                           clp_error* err)
   {
-    CoinPackedMatrix* pm = (CoinPackedMatrix*)matrix;
-    *starts = pm->getVectorStarts();
-    *lengths = pm->getVectorLengths();
-    *indices = pm->getIndices();
-    *elements = pm->getElements();
+    try {
+      CoinPackedMatrix* pm = (CoinPackedMatrix*)matrix;
+      *starts = pm->getVectorStarts();
+      *lengths = pm->getVectorLengths();
+      *indices = pm->getIndices();
+      *elements = pm->getElements();
+    }
+    CATCH_ERRORS(err)
   }
 
   // Create a new ClpSimplex.
-  clp_object* new_simplex_model (void)
+  clp_object* new_simplex_model (clp_error* err)
   {
-    ClpSimplex* model = new ClpSimplex();
-    model->messageHandler()->setLogLevel(0);  // Not Go-like to log to a hard-wired location.
-    return (clp_object*)model;
+    try {
+      ClpSimplex* model = new ClpSimplex();
+      model->messageHandler()->setLogLevel(0);  // Not Go-like to log to a hard-wired location.
+      return (clp_object*)model;
+    }
+    CATCH_ERRORS(err)
+    return NULL;
   }
 
   // Free an existing ClpSimplex.
-  void free_simplex_model (clp_object* model)
+  void free_simplex_model (clp_object* model, clp_error* err)
   {
-    delete (ClpSimplex*)model;
+    try {
+      // ClpModel does not take ownership of a message handler passed in
+      // by simplex_set_log_handler, so we delete it ourselves.
+      ClpSimplex* clp = (ClpSimplex*)model;
+      CoinMessageHandler* handler = clp->defaultHandler() ? NULL : clp->messageHandler();
+      delete clp;
+      delete handler;
+    }
+    CATCH_ERRORS(err)
   }
 
-  // Load a problem into a ClpSimplex.
+  // Load a problem into a ClpSimplex.  The matrix may be column- or
+  // row-ordered; ClpModel::loadProblem handles either.
   void simplex_load_problem (clp_object* model, clp_object* matrix,
                              const double* collb,
                              const double* colub,
                              const double* obj,
                              const double* rowlb,
                              const double* rowub,
-                             const double* rowObj)
+                             const double* rowObj,
+                             clp_error* err)
+  {
+    try {
+      ((ClpSimplex*)model)->loadProblem(*(CoinPackedMatrix*)matrix,
+                                        collb, colub, obj,
+                                        rowlb, rowub, rowObj);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Load a problem whose matrix is given in compressed sparse column form.
+  // starts has ncols+1 entries.
+  void simplex_load_problem_csc (clp_object* model, int ncols, int nrows,
+                                 const int* starts, const int* indices,
+                                 const double* elements,
+                                 const double* collb,
+                                 const double* colub,
+                                 const double* obj,
+                                 const double* rowlb,
+                                 const double* rowub,
+                                 const double* rowObj,
+                                 clp_error* err)
+  {
+    try {
+      std::vector<CoinBigIndex> st(starts, starts + ncols + 1);
+      ((ClpSimplex*)model)->loadProblem(ncols, nrows, &st[0], indices, elements,
+                                        collb, colub, obj,
+                                        rowlb, rowub, rowObj);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Append rows to a ClpSimplex.  rowStarts has number+1 entries.
+  void simplex_add_rows (clp_object* model, int number,
+                         const double* rowLower, const double* rowUpper,
+                         const int* rowStarts, const int* columns,
+                         const double* elements, clp_error* err)
+  {
+    try {
+      std::vector<CoinBigIndex> starts(rowStarts, rowStarts + number + 1);
+      ((ClpSimplex*)model)->addRows(number, rowLower, rowUpper,
+                                    &starts[0], columns, elements);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Append columns to a ClpSimplex.  colStarts has number+1 entries.
+  void simplex_add_cols (clp_object* model, int number,
+                         const double* colLower, const double* colUpper,
+                         const double* obj, const int* colStarts,
+                         const int* rows, const double* elements,
+                         clp_error* err)
+  {
+    try {
+      std::vector<CoinBigIndex> starts(colStarts, colStarts + number + 1);
+      ((ClpSimplex*)model)->addColumns(number, colLower, colUpper, obj,
+                                       &starts[0], rows, elements);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Delete a number of rows from a ClpSimplex.
+  void simplex_delete_rows (clp_object* model, int number, const int* which, clp_error* err)
+  {
+    try {
+      ((ClpSimplex*)model)->deleteRows(number, which);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Delete a number of columns from a ClpSimplex.
+  void simplex_delete_cols (clp_object* model, int number, const int* which, clp_error* err)
+  {
+    try {
+      ((ClpSimplex*)model)->deleteColumns(number, which);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Set the lower and upper bound on a single column.
+  void simplex_set_col_bounds (clp_object* model, int col, double lower, double upper, clp_error* err)
   {
-    ((ClpSimplex*)model)->loadProblem(*(CoinPackedMatrix*)matrix,
-                                      collb, colub, obj,
-                                      rowlb, rowub, rowObj);
+    try {
+      ((ClpSimplex*)model)->setColumnBounds(col, lower, upper);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Set the lower and upper bound on a single row.
+  void simplex_set_row_bounds (clp_object* model, int row, double lower, double upper, clp_error* err)
+  {
+    try {
+      ((ClpSimplex*)model)->setRowBounds(row, lower, upper);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Set the bounds on a list of columns.  bounds alternates lower and upper
+  // bounds.
+  void simplex_set_col_set_bounds (clp_object* model, int n, const int* which,
+                                   const double* bounds, clp_error* err)
+  {
+    try {
+      ((ClpSimplex*)model)->setColumnSetBounds(which, which + n, bounds);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Set the bounds on a list of rows.  bounds alternates lower and upper
+  // bounds.
+  void simplex_set_row_set_bounds (clp_object* model, int n, const int* which,
+                                   const double* bounds, clp_error* err)
+  {
+    try {
+      ((ClpSimplex*)model)->setRowSetBounds(which, which + n, bounds);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Set a list of objective-function coefficients.  If which is NULL, set
+  // the coefficients of columns 0 through n-1.
+  void simplex_set_obj_coeffs (clp_object* model, int n, const int* which,
+                               const double* values, clp_error* err)
+  {
+    try {
+      ClpSimplex* clp = (ClpSimplex*)model;
+      for (int i = 0; i < n; i++)
+        clp->setObjectiveCoefficient(which == NULL ? i : which[i], values[i]);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Modify a list of elements in the constraint matrix.  Because this can
+  // change the matrix's structure, tell the solver to rebuild its working
+  // copies (but not to discard the basis) on the next solve.
+  void simplex_modify_coeffs (clp_object* model, int n, const int* rows,
+                              const int* cols, const double* values,
+                              clp_error* err)
+  {
+    try {
+      ClpSimplex* clp = (ClpSimplex*)model;
+      for (int i = 0; i < n; i++)
+        clp->modifyCoefficient(rows[i], cols[i], values[i]);
+      ClpPackedMatrix* pm = dynamic_cast<ClpPackedMatrix*>(clp->clpMatrix());
+      if (pm != NULL)
+        pm->checkGaps();
+      clp->setWhatsChanged(0);
+    }
+    CATCH_ERRORS(err)
   }
 
   // Set the optimization direction.
-  void simplex_set_opt_dir (clp_object* model, double dir)
+  void simplex_set_opt_dir (clp_object* model, double dir, clp_error* err)
   {
-    ((ClpSimplex*)model)->setOptimizationDirection(dir);
+    try {
+      ((ClpSimplex*)model)->setOptimizationDirection(dir);
+    }
+    CATCH_ERRORS(err)
   }
 
-  void simplex_primal_set_tolerance(clp_object* model, double tolerance)
+  void simplex_primal_set_tolerance(clp_object* model, double tolerance, clp_error* err)
   {
-    ((ClpSimplex*)model)->setPrimalTolerance(tolerance);
+    try {
+      ((ClpSimplex*)model)->setPrimalTolerance(tolerance);
+    }
+    CATCH_ERRORS(err)
   }
 
-  double simplex_primal_get_tolerance(clp_object* model)
+  double simplex_primal_get_tolerance(clp_object* model, clp_error* err)
   {
-    return ((ClpSimplex*)model)->primalTolerance();
+    try {
+      return ((ClpSimplex*)model)->primalTolerance();
+    }
+    CATCH_ERRORS(err)
+    return 0.0;
   }
 
   // Solve a model using the primal method.
-  int simplex_primal (clp_object* model, int vp, int sfo)
+  int simplex_primal (clp_object* model, int vp, int sfo, clp_error* err)
   {
-    return ((ClpSimplex*)model)->primal(vp, sfo);
+    try {
+      return ((ClpSimplex*)model)->primal(vp, sfo);
+    }
+    CATCH_ERRORS(err)
+    return 0;
   }
 
   // Solve a model using the dual method.
-  int simplex_dual (clp_object* model, int vp, int sfo)
+  int simplex_dual (clp_object* model, int vp, int sfo, clp_error* err)
   {
-    return ((ClpSimplex*)model)->dual(vp, sfo);
+    try {
+      return ((ClpSimplex*)model)->dual(vp, sfo);
+    }
+    CATCH_ERRORS(err)
+    return 0;
   }
 
   // Solve a model using the barrier method.
-  int simplex_barrier (clp_object* model, int xover)
+  int simplex_barrier (clp_object* model, int xover, clp_error* err)
   {
-    return ((ClpSimplex*)model)->barrier(bool(xover));
+    try {
+      return ((ClpSimplex*)model)->barrier(bool(xover));
+    }
+    CATCH_ERRORS(err)
+    return 0;
   }
 
   // Solve a model using the reduced-gradient method.
-  int simplex_red_grad (clp_object* model, int phase)
+  int simplex_red_grad (clp_object* model, int phase, clp_error* err)
+  {
+    try {
+      return ((ClpSimplex*)model)->reducedGradient(phase);
+    }
+    CATCH_ERRORS(err)
+    return 0;
+  }
+
+  // Solve a model with ClpSimplex::initialSolve, configuring it the way the
+  // standalone clp program does.  type is 0 for automatic, 1 for dual, 2 for
+  // primal or sprint, 3 for barrier, and 4 for barrier without crossover.
+  // presolve_passes is 0 to disable presolve.  idiot and sprint are -1 to
+  // let CLP decide, 0 to disable the heuristic, or a number of passes.
+  int simplex_initial_solve (clp_object* model, int type, int presolve_passes,
+                             int crash, int idiot, int sprint, clp_error* err)
   {
-    return ((ClpSimplex*)model)->reducedGradient(phase);
+    try {
+      static const ClpSolve::SolveType types[] = {
+        ClpSolve::automatic, ClpSolve::useDual, ClpSolve::usePrimalorSprint,
+        ClpSolve::useBarrier, ClpSolve::useBarrierNoCross
+      };
+      ClpSolve opts;
+      if (presolve_passes == 0)
+        opts.setPresolveType(ClpSolve::presolveOff);
+      else if (presolve_passes == 5)
+        opts.setPresolveType(ClpSolve::presolveOn, presolve_passes);
+      else
+        opts.setPresolveType(ClpSolve::presolveNumber, presolve_passes);
+      if (type == 1) {
+        if (crash)
+          opts.setSpecialOption(0, 1);
+        else if (idiot)
+          opts.setSpecialOption(0, 2, idiot);
+      } else if (type == 2) {
+        if (crash)
+          opts.setSpecialOption(1, 1);
+        else if (sprint > 0)
+          opts.setSpecialOption(1, 3, sprint);
+        else if (idiot > 0)
+          opts.setSpecialOption(1, 2, idiot);
+        else if (idiot == 0)
+          opts.setSpecialOption(1, sprint == 0 ? 4 : 9);
+        else
+          opts.setSpecialOption(1, sprint == 0 ? 8 : 7);
+      }
+      opts.setSolveType(types[type]);
+      return ((ClpSimplex*)model)->initialSolve(opts);
+    }
+    CATCH_ERRORS(err)
+    return 0;
   }
 
   // Retrieve a simplex's rows and columns.
-  void simplex_get_dims (clp_object* model, int* nrows, int* ncols)
+  void simplex_get_dims (clp_object* model, int* nrows, int* ncols, clp_error* err)
   {
-    *nrows = ((ClpSimplex*)model)->getNumRows();
-    *ncols = ((ClpSimplex*)model)->getNumCols();
+    try {
+      *nrows = ((ClpSimplex*)model)->getNumRows();
+      *ncols = ((ClpSimplex*)model)->getNumCols();
+    }
+    CATCH_ERRORS(err)
   }
 
   // Set or unset problem scaling.
-  void simplex_scaling (clp_object* model, int mode)
+  void simplex_scaling (clp_object* model, int mode, clp_error* err)
   {
-    ((ClpSimplex*)model)->scaling(mode);
+    try {
+      ((ClpSimplex*)model)->scaling(mode);
+    }
+    CATCH_ERRORS(err)
   }
 
   // Return a model's primal column solution.
-  double* simplex_get_prim_col_soln (clp_object* model)
+  double* simplex_get_prim_col_soln (clp_object* model, clp_error* err)
   {
-    return ((ClpSimplex*)model)->primalColumnSolution();
+    try {
+      return ((ClpSimplex*)model)->primalColumnSolution();
+    }
+    CATCH_ERRORS(err)
+    return NULL;
   }
 
   // Return a model's dual column solution.
-  double* simplex_get_dual_col_soln (clp_object* model)
+  double* simplex_get_dual_col_soln (clp_object* model, clp_error* err)
   {
-    return ((ClpSimplex*)model)->dualColumnSolution();
+    try {
+      return ((ClpSimplex*)model)->dualColumnSolution();
+    }
+    CATCH_ERRORS(err)
+    return NULL;
   }
 
   // Return a model's primal row solution.
-  double* simplex_get_prim_row_soln (clp_object* model)
+  double* simplex_get_prim_row_soln (clp_object* model, clp_error* err)
   {
-    return ((ClpSimplex*)model)->primalRowSolution();
+    try {
+      return ((ClpSimplex*)model)->primalRowSolution();
+    }
+    CATCH_ERRORS(err)
+    return NULL;
   }
 
   // Return a model's dual row solution.
-  double* simplex_get_dual_row_soln (clp_object* model)
+  double* simplex_get_dual_row_soln (clp_object* model, clp_error* err)
   {
-    return ((ClpSimplex*)model)->dualRowSolution();
+    try {
+      return ((ClpSimplex*)model)->dualRowSolution();
+    }
+    CATCH_ERRORS(err)
+    return NULL;
   }
 
   // Return the value of the objective function.
-  double simplex_obj_val (clp_object* model)
+  double simplex_obj_val (clp_object* model, clp_error* err)
+  {
+    try {
+      return ((ClpSimplex*)model)->objectiveValue();
+    }
+    CATCH_ERRORS(err)
+    return 0.0;
+  }
+
+  // Return a model's column lower bounds.
+  const double* simplex_get_col_lower (clp_object* model, clp_error* err)
+  {
+    try {
+      return ((ClpModel*)model)->getColLower();
+    }
+    CATCH_ERRORS(err)
+    return NULL;
+  }
+
+  // Return a model's column upper bounds.
+  const double* simplex_get_col_upper (clp_object* model, clp_error* err)
+  {
+    try {
+      return ((ClpModel*)model)->getColUpper();
+    }
+    CATCH_ERRORS(err)
+    return NULL;
+  }
+
+  // Return a model's objective-function coefficients.
+  const double* simplex_get_obj (clp_object* model, clp_error* err)
+  {
+    try {
+      return ((ClpModel*)model)->getObjCoefficients();
+    }
+    CATCH_ERRORS(err)
+    return NULL;
+  }
+
+  // Return a model's row lower bounds.
+  const double* simplex_get_row_lower (clp_object* model, clp_error* err)
+  {
+    try {
+      return ((ClpModel*)model)->getRowLower();
+    }
+    CATCH_ERRORS(err)
+    return NULL;
+  }
+
+  // Return a model's row upper bounds.
+  const double* simplex_get_row_upper (clp_object* model, clp_error* err)
+  {
+    try {
+      return ((ClpModel*)model)->getRowUpper();
+    }
+    CATCH_ERRORS(err)
+    return NULL;
+  }
+
+  // Return the optimization direction.
+  double simplex_get_opt_dir (clp_object* model, clp_error* err)
+  {
+    try {
+      return ((ClpModel*)model)->optimizationDirection();
+    }
+    CATCH_ERRORS(err)
+    return 0.0;
+  }
+
+  // Return the constant term that CLP subtracts from the objective value.
+  double simplex_get_obj_offset (clp_object* model, clp_error* err)
+  {
+    try {
+      return ((ClpModel*)model)->objectiveOffset();
+    }
+    CATCH_ERRORS(err)
+    return 0.0;
+  }
+
+  // Set the constant term that CLP subtracts from the objective value.
+  void simplex_set_obj_offset (clp_object* model, double offset, clp_error* err)
+  {
+    try {
+      ((ClpModel*)model)->setObjectiveOffset(offset);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Return a new CoinPackedMatrix that contains a copy of a model's
+  // constraint matrix.
+  clp_object* simplex_get_matrix (clp_object* model, clp_error* err)
+  {
+    try {
+      CoinPackedMatrix* matrix = ((ClpModel*)model)->matrix();
+      if (matrix == NULL)
+        return (clp_object*)new CoinPackedMatrix();
+      return (clp_object*)new CoinPackedMatrix(*matrix);
+    }
+    CATCH_ERRORS(err)
+    return NULL;
+  }
+
+  // Return a row's name as a malloc'ed string.  Like CLP's MPS writer, we
+  // make up a name if none was assigned.
+  char* simplex_get_row_name (clp_object* model, int row, clp_error* err)
+  {
+    try {
+      std::string name = ((ClpModel*)model)->getRowName(row);
+      if (name.empty()) {
+        char buf[32];
+        sprintf(buf, "R%7.7d", row);
+        name = buf;
+      }
+      return strdup(name.c_str());
+    }
+    CATCH_ERRORS(err)
+    return NULL;
+  }
+
+  // Return a column's name as a malloc'ed string.  Like CLP's MPS writer, we
+  // make up a name if none was assigned.
+  char* simplex_get_col_name (clp_object* model, int col, clp_error* err)
+  {
+    try {
+      std::string name = ((ClpModel*)model)->getColumnName(col);
+      if (name.empty()) {
+        char buf[32];
+        sprintf(buf, "C%7.7d", col);
+        name = buf;
+      }
+      return strdup(name.c_str());
+    }
+    CATCH_ERRORS(err)
+    return NULL;
+  }
+
+  // Return the problem's name, as read from an MPS file, as a malloc'ed
+  // string.
+  char* simplex_get_problem_name (clp_object* model, clp_error* err)
+  {
+    try {
+      return strdup(((ClpModel*)model)->problemName().c_str());
+    }
+    CATCH_ERRORS(err)
+    return NULL;
+  }
+
+  // Assign a name to a row.
+  void simplex_set_row_name (clp_object* model, int row, const char* name, clp_error* err)
+  {
+    try {
+      std::string str(name);
+      ((ClpModel*)model)->setRowName(row, str);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Assign a name to a column.
+  void simplex_set_col_name (clp_object* model, int col, const char* name, clp_error* err)
+  {
+    try {
+      std::string str(name);
+      ((ClpModel*)model)->setColumnName(col, str);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Assign names to n consecutive rows.
+  void simplex_set_row_names (clp_object* model, int first, int n, char** names, clp_error* err)
+  {
+    try {
+      for (int i = 0; i < n; i++) {
+        std::string str(names[i]);
+        ((ClpModel*)model)->setRowName(first + i, str);
+      }
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Assign names to n consecutive columns.
+  void simplex_set_col_names (clp_object* model, int first, int n, char** names, clp_error* err)
+  {
+    try {
+      for (int i = 0; i < n; i++) {
+        std::string str(names[i]);
+        ((ClpModel*)model)->setColumnName(first + i, str);
+      }
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Install an event handler that invokes the Go callback associated with
+  // handle, or remove any existing handler if handle is 0.
+  void simplex_set_event_handler (clp_object* model, uintptr_t handle, clp_error* err)
+  {
+    try {
+      ClpSimplex* clp = (ClpSimplex*)model;
+      if (handle == 0) {
+        ClpEventHandler dflt;
+        clp->passInEventHandler(&dflt);
+      } else {
+        GoEventHandler handler(handle);
+        clp->passInEventHandler(&handler);
+      }
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Route a model's log messages to the Go callback associated with handle,
+  // or to standard output if handle is 0.  The log level is preserved.
+  void simplex_set_log_handler (clp_object* model, uintptr_t handle, clp_error* err)
+  {
+    try {
+      ClpSimplex* clp = (ClpSimplex*)model;
+      CoinMessageHandler* old = clp->defaultHandler() ? NULL : clp->messageHandler();
+      CoinMessageHandler* handler;
+      if (handle == 0)
+        handler = new CoinMessageHandler();
+      else
+        handler = new GoMessageHandler(handle);
+      handler->setLogLevel(clp->logLevel());
+      clp->passInMessageHandler(handler);
+      delete old;
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Set the amount of logging a model performs.
+  void simplex_set_log_level (clp_object* model, int level, clp_error* err)
+  {
+    try {
+      ((ClpModel*)model)->setLogLevel(level);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Return the amount of logging a model performs.
+  int simplex_get_log_level (clp_object* model, clp_error* err)
+  {
+    try {
+      return ((ClpModel*)model)->logLevel();
+    }
+    CATCH_ERRORS(err)
+    return 0;
+  }
+
+  // Say whether a column is integer-valued.
+  int simplex_is_integer (clp_object* model, int col, clp_error* err)
+  {
+    try {
+      return int(((ClpModel*)model)->isInteger(col));
+    }
+    CATCH_ERRORS(err)
+    return 0;
+  }
+
+  // Mark a column as integer-valued.
+  void simplex_set_integer (clp_object* model, int col, clp_error* err)
+  {
+    try {
+      ((ClpModel*)model)->setInteger(col);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Return a ClpSimplex, creating its status array if it does not yet have
+  // one.  The initial status is an all-slack basis.
+  static ClpSimplex* with_status (clp_object* model)
+  {
+    ClpSimplex* clp = (ClpSimplex*)model;
+    if (clp->statusArray() == NULL)
+      clp->createStatus();
+    return clp;
+  }
+
+  // Return the basis status of a column.
+  int simplex_get_col_status (clp_object* model, int col, clp_error* err)
+  {
+    try {
+      return int(with_status(model)->getColumnStatus(col));
+    }
+    CATCH_ERRORS(err)
+    return 0;
+  }
+
+  // Return the basis status of a row.
+  int simplex_get_row_status (clp_object* model, int row, clp_error* err)
+  {
+    try {
+      return int(with_status(model)->getRowStatus(row));
+    }
+    CATCH_ERRORS(err)
+    return 0;
+  }
+
+  // Set the basis status of a column.
+  void simplex_set_col_status (clp_object* model, int col, int status, clp_error* err)
+  {
+    try {
+      with_status(model)->setColumnStatus(col, ClpSimplex::Status(status));
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Set the basis status of a row.
+  void simplex_set_row_status (clp_object* model, int row, int status, clp_error* err)
+  {
+    try {
+      with_status(model)->setRowStatus(row, ClpSimplex::Status(status));
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Copy the basis status of every column and row into the given arrays.
+  void simplex_get_basis (clp_object* model, int* colStatus, int* rowStatus, clp_error* err)
+  {
+    try {
+      ClpSimplex* clp = with_status(model);
+      int ncols = clp->getNumCols();
+      int nrows = clp->getNumRows();
+      for (int i = 0; i < ncols; i++)
+        colStatus[i] = int(clp->getColumnStatus(i));
+      for (int i = 0; i < nrows; i++)
+        rowStatus[i] = int(clp->getRowStatus(i));
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Set the basis status of every column and row from the given arrays.
+  void simplex_set_basis (clp_object* model, const int* colStatus, const int* rowStatus, clp_error* err)
+  {
+    try {
+      ClpSimplex* clp = with_status(model);
+      int ncols = clp->getNumCols();
+      int nrows = clp->getNumRows();
+      for (int i = 0; i < ncols; i++)
+        clp->setColumnStatus(i, ClpSimplex::Status(colStatus[i]));
+      for (int i = 0; i < nrows; i++)
+        clp->setRowStatus(i, ClpSimplex::Status(rowStatus[i]));
+    }
+    CATCH_ERRORS(err)
+  }
+
+  void set_max_iterations(clp_object* model, int max_iter, clp_error* err)
+  {
+    try {
+      ((ClpModel*)model)->setMaximumIterations(max_iter);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  int max_iterations(clp_object* model, clp_error* err)
+  {
+    try {
+      return ((ClpModel*)model)->maximumIterations();
+    }
+    CATCH_ERRORS(err)
+    return 0;
+  }
+
+  int number_iterations(clp_object* model, clp_error* err)
+  {
+    try {
+      return ((ClpModel*)model)->numberIterations();
+    }
+    CATCH_ERRORS(err)
+    return 0;
+  }
+
+  void set_max_seconds(clp_object* model, double max_seconds, clp_error* err)
+  {
+    try {
+      ((ClpModel*)model)->setMaximumSeconds(max_seconds);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  double max_seconds(clp_object* model, clp_error* err)
+  {
+    try {
+      return ((ClpModel*)model)->maximumSeconds();
+    }
+    CATCH_ERRORS(err)
+    return 0.0;
+  }
+
+  // Return a new ClpPresolve.
+  clp_object* new_presolve (clp_error* err)
+  {
+    try {
+      return (clp_object*)new ClpPresolve();
+    }
+    CATCH_ERRORS(err)
+    return NULL;
+  }
+
+  // Free a ClpPresolve.  This does not free the presolved model.
+  void free_presolve (clp_object* presolve, clp_error* err)
+  {
+    try {
+      delete (ClpPresolve*)presolve;
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Presolve a model, returning a new, reduced model, which the caller must
+  // free, or NULL if presolve finds the problem infeasible or unbounded.  The
+  // reduced model starts with CLP's default event handler and its own,
+  // silent message handler so that freeing it does not disturb the original
+  // model's handlers.
+  clp_object* presolve_model (clp_object* presolve, clp_object* model,
+                              double tolerance, int passes, int drop_names,
+                              clp_error* err)
+  {
+    try {
+      ClpSimplex* reduced =
+        ((ClpPresolve*)presolve)->presolvedModel(*(ClpSimplex*)model, tolerance,
+                                                 true, passes, bool(drop_names));
+      if (reduced == NULL)
+        return NULL;
+      ClpEventHandler dflt;
+      reduced->passInEventHandler(&dflt);
+      CoinMessageHandler* handler = new CoinMessageHandler();
+      handler->setLogLevel(0);
+      reduced->passInMessageHandler(handler);
+      return (clp_object*)reduced;
+    }
+    CATCH_ERRORS(err)
+    return NULL;
+  }
+
+  // Copy the original row and column numbers of each row and column of a
+  // presolved model.
+  void presolve_original_indices (clp_object* presolve, int nrows, int* rows,
+                                  int ncols, int* cols, clp_error* err)
+  {
+    try {
+      ClpPresolve* pre = (ClpPresolve*)presolve;
+      std::copy(pre->originalRows(), pre->originalRows() + nrows, rows);
+      std::copy(pre->originalColumns(), pre->originalColumns() + ncols, cols);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Map the solution and basis of a presolved model back to the original
+  // model.
+  void postsolve (clp_object* presolve, clp_error* err)
+  {
+    try {
+      ((ClpPresolve*)presolve)->postsolve(true);
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Select the pricing algorithm used by the primal method: Dantzig pricing
+  // if mode is negative or steepest-edge pricing with the given mode
+  // otherwise.
+  void simplex_set_primal_pricing(clp_object* model, int mode, clp_error* err)
+  {
+    try {
+      ClpSimplex* clp = (ClpSimplex*)model;
+      if (mode < 0) {
+        ClpPrimalColumnDantzig dantzig;
+        clp->setPrimalColumnPivotAlgorithm(dantzig);
+      } else {
+        ClpPrimalColumnSteepest steep(mode);
+        clp->setPrimalColumnPivotAlgorithm(steep);
+      }
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Select the pricing algorithm used by the dual method: Dantzig pricing if
+  // mode is negative or steepest-edge pricing with the given mode otherwise.
+  void simplex_set_dual_pricing(clp_object* model, int mode, clp_error* err)
+  {
+    try {
+      ClpSimplex* clp = (ClpSimplex*)model;
+      if (mode < 0) {
+        ClpDualRowDantzig dantzig;
+        clp->setDualRowPivotAlgorithm(dantzig);
+      } else {
+        ClpDualRowSteepest steep(mode);
+        clp->setDualRowPivotAlgorithm(steep);
+      }
+    }
+    CATCH_ERRORS(err)
+  }
+
+  // Retrieve a model's solver parameters.
+  void simplex_get_params(clp_object* model, clp_params* params, clp_error* err)
   {
-    return ((ClpSimplex*)model)->objectiveValue();
+    try {
+      ClpSimplex* clp = (ClpSimplex*)model;
+      params->max_iterations = clp->maximumIterations();
+      clp->getIntParam(ClpMaxNumIterationHotStart, params->max_iterations_hot_start);
+      clp->getIntParam(ClpNameDiscipline, params->name_discipline);
+      params->perturbation = clp->perturbation();
+      params->factorization_frequency = clp->factorizationFrequency();
+      params->max_seconds = clp->maximumSeconds();
+      params->max_wall_seconds = clp->maximumWallSeconds();
+      params->primal_tolerance = clp->primalTolerance();
+      params->dual_tolerance = clp->dualTolerance();
+      params->primal_objective_limit = clp->primalObjectiveLimit();
+      params->dual_objective_limit = clp->dualObjectiveLimit();
+      clp->getDblParam(ClpPresolveTolerance, params->presolve_tolerance);
+      params->dual_bound = clp->dualBound();
+      params->infeasibility_cost = clp->infeasibilityCost();
+    }
+    CATCH_ERRORS(err)
   }
 
-  void set_max_iterations(clp_object* model, int max_iter)
+  // Assign a model's solver parameters.  The Go caller has already checked
+  // that they lie within range.
+  void simplex_set_params(clp_object* model, const clp_params* params, clp_error* err)
   {
-    ((ClpModel*)model)->setMaximumIterations(max_iter);
+    try {
+      ClpSimplex* clp = (ClpSimplex*)model;
+      clp->setMaximumIterations(params->max_iterations);
+      clp->setIntParam(ClpMaxNumIterationHotStart, params->max_iterations_hot_start);
+      clp->setIntParam(ClpNameDiscipline, params->name_discipline);
+      clp->setPerturbation(params->perturbation);
+      clp->setFactorizationFrequency(params->factorization_frequency);
+      clp->setMaximumSeconds(params->max_seconds);
+      clp->setMaximumWallSeconds(params->max_wall_seconds);
+      clp->setPrimalTolerance(params->primal_tolerance);
+      clp->setDualTolerance(params->dual_tolerance);
+      clp->setPrimalObjectiveLimit(params->primal_objective_limit);
+      clp->setDualObjectiveLimit(params->dual_objective_limit);
+      clp->setDblParam(ClpPresolveTolerance, params->presolve_tolerance);
+      clp->setDualBound(params->dual_bound);
+      clp->setInfeasibilityCost(params->infeasibility_cost);
+    }
+    CATCH_ERRORS(err)
   }
 
-  int max_iterations(clp_object* model)
+  int secondary_status(clp_object* model, clp_error* err)
   {
-    return ((ClpModel*)model)->maximumIterations();
+    try {
+      return ((ClpModel*)model)->secondaryStatus();
+    }
+    CATCH_ERRORS(err)
+    return 0;
   }
 
-  void set_max_seconds(clp_object* model, double max_seconds)
+  void set_secondary_status(clp_object* model, int status, clp_error* err)
   {
-    ((ClpModel*)model)->setMaximumSeconds(max_seconds);
+    try {
+      ((ClpModel*)model)->setSecondaryStatus(status);
+    }
+    CATCH_ERRORS(err)
   }
 
-  double max_seconds(clp_object* model)
+  // Retrieve statistics about the most recent solve.
+  void simplex_get_solve_stats (clp_object* model, double* obj, int* iters,
+                                int* nPrimalInf, double* sumPrimalInf,
+                                int* nDualInf, double* sumDualInf,
+                                clp_error* err)
   {
-    return ((ClpModel*)model)->maximumSeconds();
+    try {
+      ClpSimplex* clp = (ClpSimplex*)model;
+      *obj = clp->objectiveValue();
+      *iters = clp->numberIterations();
+      *nPrimalInf = clp->numberPrimalInfeasibilities();
+      *sumPrimalInf = clp->sumPrimalInfeasibilities();
+      *nDualInf = clp->numberDualInfeasibilities();
+      *sumDualInf = clp->sumDualInfeasibilities();
+    }
+    CATCH_ERRORS(err)
   }
 
-  int secondary_status(clp_object* model)
+  int write_mps(clp_object* model, const char * filename, clp_error* err)
   {
-    return ((ClpModel*)model)->secondaryStatus();
+    try {
+      return ((ClpModel*)model)->writeMps(filename);
+    }
+    CATCH_ERRORS(err)
+    return 0;
   }
 
-  int write_mps(clp_object* model, const char * filename)
+  // Read a model from an MPS file.  CoinMpsIO, which does the actual work,
+  // accepts both fixed and free format and transparently decompresses
+  // gzipped input.
+  int read_mps(clp_object* model, const char * filename,
+               int keep_names, int ignore_errors, clp_error* err)
   {
-    return ((ClpModel*)model)->writeMps(filename);
+    try {
+      return ((ClpSimplex*)model)->readMps(filename, bool(keep_names), bool(ignore_errors));
+    }
+    CATCH_ERRORS(err)
+    return 0;
   }
 
   int primal_ranging(clp_object* model, const int number_check, const int* which,
                      double* value_increase, int* sequence_increase,
-                     double* value_decrease, int* sequence_decrease)
+                     double* value_decrease, int* sequence_decrease, clp_error* err)
   {
-    return ((ClpSimplex*)model)->primalRanging(number_check, which,
-                                               value_increase, sequence_increase,
-                                               value_decrease, sequence_decrease);
+    try {
+      return ((ClpSimplex*)model)->primalRanging(number_check, which,
+                                                 value_increase, sequence_increase,
+                                                 value_decrease, sequence_decrease);
+    }
+    CATCH_ERRORS(err)
+    return 0;
   }
 
   int dual_ranging(clp_object* model, const int number_check, const int* which,
                    double* cost_increase, int* sequence_increase,
                    double* cost_decrease, int* sequence_decrease,
-                   double* value_increase, double* value_decrease)
+                   double* value_increase, double* value_decrease, clp_error* err)
   {
-    return ((ClpSimplex*)model)->dualRanging(number_check, which,
-                                             cost_increase, sequence_increase,
-                                             cost_decrease, sequence_decrease,
-                                             value_increase, value_decrease);
+    try {
+      return ((ClpSimplex*)model)->dualRanging(number_check, which,
+                                               cost_increase, sequence_increase,
+                                               cost_decrease, sequence_decrease,
+                                               value_increase, value_decrease);
+    }
+    CATCH_ERRORS(err)
+    return 0;
   }
 }
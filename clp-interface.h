@@ -1,6 +1,8 @@
 #ifndef _CLP_INTERFACE_H_
 #define _CLP_INTERFACE_H_
 
+#include <stdint.h>
+
 #ifdef __cplusplus
 extern "C" {
 #endif
@@ -8,57 +10,176 @@ extern "C" {
   // A clp_object* is an opaque pointer to an arbitrary C++ object.
   typedef char clp_object;
 
+  // A clp_error reports a C++ exception caught by a wrapper function.
+  // Every wrapper function takes a clp_error* as its final argument.  The
+  // caller initializes it to zero.  If CLP throws an exception, the wrapper
+  // sets code to one of the values below and message to a malloc'ed
+  // description of the exception (or NULL if memory is exhausted), which the
+  // caller must free.  The wrapper's return value is then meaningless.
+  typedef struct {
+    int code;
+    char* message;
+  } clp_error;
+
+  // These are the possible values of a clp_error's code.
+  enum {
+    CLP_OK = 0,                // No exception was thrown
+    CLP_COIN_ERROR = 1,        // CLP or CoinUtils threw a CoinError
+    CLP_BAD_ALLOC = 2,         // Memory was exhausted
+    CLP_STD_EXCEPTION = 3,     // Some other std::exception was thrown
+    CLP_UNKNOWN_EXCEPTION = 4  // Something other than a std::exception was thrown
+  };
+
+  // A clp_params holds the solver parameters that simplex_get_params and
+  // simplex_set_params transfer in bulk.
+  typedef struct {
+    int max_iterations;
+    int max_iterations_hot_start;
+    int name_discipline;
+    int perturbation;
+    int factorization_frequency;
+    double max_seconds;
+    double max_wall_seconds;
+    double primal_tolerance;
+    double dual_tolerance;
+    double primal_objective_limit;
+    double dual_objective_limit;
+    double presolve_tolerance;
+    double dual_bound;
+    double infeasibility_cost;
+  } clp_params;
+
   // Declare all of our wrapper functions.
-  extern clp_object* new_packed_matrix (void);
-  extern void reserve (clp_object* matrix, int newMaxMajorDim, int newMaxSize, int create);
-  extern void set_dimensions (clp_object* matrix, int numrows, int numcols);
-  extern void free_packed_matrix (clp_object* matrix);
+  extern clp_object* new_packed_matrix (clp_error* err);
+  extern void reserve (clp_object* matrix, int newMaxMajorDim, int newMaxSize, int create, clp_error* err);
+  extern void set_dimensions (clp_object* matrix, int numrows, int numcols, clp_error* err);
+  extern void free_packed_matrix (clp_object* matrix, clp_error* err);
   extern void pm_append_col (clp_object* matrix, const int vecsize,
-                             const int* vecind, const double* vecelem);
+                             const int* vecind, const double* vecelem, clp_error* err);
   extern void pm_append_row (clp_object* matrix, const int vecsize,
-                             const int* vecind, const double* vecelem);
+                             const int* vecind, const double* vecelem, clp_error* err);
   extern void pm_delete_cols (clp_object* matrix, const int ncols,
-                              const int* columns);
+                              const int* columns, clp_error* err);
   extern void pm_delete_rows (clp_object* matrix, const int nrows,
-                              const int* rows);
-  extern void pm_get_dims (clp_object* matrix, int* nrows, int* ncols);
+                              const int* rows, clp_error* err);
+  extern void pm_get_dims (clp_object* matrix, int* nrows, int* ncols, clp_error* err);
+  extern int pm_is_col_ordered (clp_object* matrix, clp_error* err);
+  extern void pm_reverse_ordering (clp_object* matrix, clp_error* err);
+  extern void pm_transpose (clp_object* matrix, clp_error* err);
   extern void pm_get_sparse_data (clp_object* matrix, const int** starts,
                                   const int** lengths, const int** indices,
-                                  const double** elements);
-  extern clp_object* new_simplex_model (void);
-  extern void free_simplex_model (clp_object* model);
+                                  const double** elements, clp_error* err);
+  extern clp_object* new_simplex_model (clp_error* err);
+  extern void free_simplex_model (clp_object* model, clp_error* err);
   extern void simplex_load_problem (clp_object* model, clp_object* matrix,
                                     const double* collb, const double* colub,
                                     const double* obj,
                                     const double* rowlb, const double* rowub,
-                                    const double* rowObj);
-  extern void simplex_set_opt_dir (clp_object* model, double dir);
-  extern int simplex_primal (clp_object* model, int vp, int sfo);
-  extern int simplex_dual (clp_object* model, int vp, int sfo);
-  extern int simplex_barrier (clp_object* model, int xover);
-  extern int simplex_red_grad (clp_object* model, int phase);
-  extern void simplex_get_dims (clp_object* model, int* nrows, int* ncols);
-  extern void simplex_scaling (clp_object* model, int mode);
-  extern double* simplex_get_prim_col_soln (clp_object* model);
-  extern double* simplex_get_dual_col_soln (clp_object* model);
-  extern double* simplex_get_prim_row_soln (clp_object* model);
-  extern double* simplex_get_dual_row_soln (clp_object* model);
-  extern double simplex_obj_val (clp_object* model);
-  extern void simplex_primal_set_tolerance(clp_object* model, double tolerance);
-  extern double simplex_primal_get_tolerance(clp_object* model);
-  extern void set_max_iterations(clp_object* model, int max_iter);
-  extern int max_iterations(clp_object* model);
-  extern void set_max_seconds(clp_object* model, double max_seconds);
-  extern double max_seconds(clp_object* model);
-  extern int secondary_status(clp_object* model);
-  extern int write_mps(clp_object* model, const char * filename);
+                                    const double* rowObj, clp_error* err);
+  extern void simplex_load_problem_csc (clp_object* model, int ncols, int nrows,
+                                        const int* starts, const int* indices,
+                                        const double* elements,
+                                        const double* collb, const double* colub,
+                                        const double* obj,
+                                        const double* rowlb, const double* rowub,
+                                        const double* rowObj, clp_error* err);
+  extern void simplex_add_rows (clp_object* model, int number,
+                                const double* rowLower, const double* rowUpper,
+                                const int* rowStarts, const int* columns,
+                                const double* elements, clp_error* err);
+  extern void simplex_add_cols (clp_object* model, int number,
+                                const double* colLower, const double* colUpper,
+                                const double* obj, const int* colStarts,
+                                const int* rows, const double* elements, clp_error* err);
+  extern void simplex_delete_rows (clp_object* model, int number, const int* which, clp_error* err);
+  extern void simplex_delete_cols (clp_object* model, int number, const int* which, clp_error* err);
+  extern void simplex_set_col_bounds (clp_object* model, int col, double lower, double upper, clp_error* err);
+  extern void simplex_set_row_bounds (clp_object* model, int row, double lower, double upper, clp_error* err);
+  extern void simplex_set_col_set_bounds (clp_object* model, int n, const int* which,
+                                          const double* bounds, clp_error* err);
+  extern void simplex_set_row_set_bounds (clp_object* model, int n, const int* which,
+                                          const double* bounds, clp_error* err);
+  extern void simplex_set_obj_coeffs (clp_object* model, int n, const int* which,
+                                      const double* values, clp_error* err);
+  extern void simplex_modify_coeffs (clp_object* model, int n, const int* rows,
+                                     const int* cols, const double* values, clp_error* err);
+  extern void simplex_set_opt_dir (clp_object* model, double dir, clp_error* err);
+  extern int simplex_primal (clp_object* model, int vp, int sfo, clp_error* err);
+  extern int simplex_dual (clp_object* model, int vp, int sfo, clp_error* err);
+  extern int simplex_barrier (clp_object* model, int xover, clp_error* err);
+  extern int simplex_initial_solve (clp_object* model, int type, int presolve_passes,
+                                    int crash, int idiot, int sprint, clp_error* err);
+  extern int simplex_red_grad (clp_object* model, int phase, clp_error* err);
+  extern void simplex_get_dims (clp_object* model, int* nrows, int* ncols, clp_error* err);
+  extern void simplex_scaling (clp_object* model, int mode, clp_error* err);
+  extern double* simplex_get_prim_col_soln (clp_object* model, clp_error* err);
+  extern double* simplex_get_dual_col_soln (clp_object* model, clp_error* err);
+  extern double* simplex_get_prim_row_soln (clp_object* model, clp_error* err);
+  extern double* simplex_get_dual_row_soln (clp_object* model, clp_error* err);
+  extern double simplex_obj_val (clp_object* model, clp_error* err);
+  extern const double* simplex_get_col_lower (clp_object* model, clp_error* err);
+  extern const double* simplex_get_col_upper (clp_object* model, clp_error* err);
+  extern const double* simplex_get_obj (clp_object* model, clp_error* err);
+  extern const double* simplex_get_row_lower (clp_object* model, clp_error* err);
+  extern const double* simplex_get_row_upper (clp_object* model, clp_error* err);
+  extern double simplex_get_opt_dir (clp_object* model, clp_error* err);
+  extern double simplex_get_obj_offset (clp_object* model, clp_error* err);
+  extern void simplex_set_obj_offset (clp_object* model, double offset, clp_error* err);
+  extern clp_object* simplex_get_matrix (clp_object* model, clp_error* err);
+  extern char* simplex_get_row_name (clp_object* model, int row, clp_error* err);
+  extern char* simplex_get_col_name (clp_object* model, int col, clp_error* err);
+  extern char* simplex_get_problem_name (clp_object* model, clp_error* err);
+  extern void simplex_set_row_name (clp_object* model, int row, const char* name, clp_error* err);
+  extern void simplex_set_col_name (clp_object* model, int col, const char* name, clp_error* err);
+  extern void simplex_set_row_names (clp_object* model, int first, int n, char** names, clp_error* err);
+  extern void simplex_set_col_names (clp_object* model, int first, int n, char** names, clp_error* err);
+  extern void simplex_set_event_handler (clp_object* model, uintptr_t handle, clp_error* err);
+  extern void simplex_set_log_handler (clp_object* model, uintptr_t handle, clp_error* err);
+  extern void simplex_set_log_level (clp_object* model, int level, clp_error* err);
+  extern int simplex_get_log_level (clp_object* model, clp_error* err);
+  extern int simplex_is_integer (clp_object* model, int col, clp_error* err);
+  extern void simplex_set_integer (clp_object* model, int col, clp_error* err);
+  extern int simplex_get_col_status (clp_object* model, int col, clp_error* err);
+  extern int simplex_get_row_status (clp_object* model, int row, clp_error* err);
+  extern void simplex_set_col_status (clp_object* model, int col, int status, clp_error* err);
+  extern void simplex_set_row_status (clp_object* model, int row, int status, clp_error* err);
+  extern void simplex_get_basis (clp_object* model, int* colStatus, int* rowStatus, clp_error* err);
+  extern void simplex_set_basis (clp_object* model, const int* colStatus, const int* rowStatus, clp_error* err);
+  extern void simplex_primal_set_tolerance(clp_object* model, double tolerance, clp_error* err);
+  extern double simplex_primal_get_tolerance(clp_object* model, clp_error* err);
+  extern void set_max_iterations(clp_object* model, int max_iter, clp_error* err);
+  extern int max_iterations(clp_object* model, clp_error* err);
+  extern int number_iterations(clp_object* model, clp_error* err);
+  extern void set_max_seconds(clp_object* model, double max_seconds, clp_error* err);
+  extern double max_seconds(clp_object* model, clp_error* err);
+  extern clp_object* new_presolve (clp_error* err);
+  extern void free_presolve (clp_object* presolve, clp_error* err);
+  extern clp_object* presolve_model (clp_object* presolve, clp_object* model,
+                                     double tolerance, int passes, int drop_names,
+                                     clp_error* err);
+  extern void presolve_original_indices (clp_object* presolve, int nrows, int* rows,
+                                         int ncols, int* cols, clp_error* err);
+  extern void postsolve (clp_object* presolve, clp_error* err);
+  extern void simplex_set_primal_pricing(clp_object* model, int mode, clp_error* err);
+  extern void simplex_set_dual_pricing(clp_object* model, int mode, clp_error* err);
+  extern void simplex_get_params(clp_object* model, clp_params* params, clp_error* err);
+  extern void simplex_set_params(clp_object* model, const clp_params* params, clp_error* err);
+  extern int secondary_status(clp_object* model, clp_error* err);
+  extern void simplex_get_solve_stats (clp_object* model, double* obj, int* iters,
+                                       int* nPrimalInf, double* sumPrimalInf,
+                                       int* nDualInf, double* sumDualInf,
+                                       clp_error* err);
+  extern void set_secondary_status(clp_object* model, int status, clp_error* err);
+  extern int write_mps(clp_object* model, const char * filename, clp_error* err);
+  extern int read_mps(clp_object* model, const char * filename,
+                      int keep_names, int ignore_errors, clp_error* err);
   extern int primal_ranging(clp_object* model, const int number_check, const int* which,
                             double* value_increase, int* sequence_increase,
-                            double* value_decrease, int* sequence_decrease);
+                            double* value_decrease, int* sequence_decrease, clp_error* err);
   extern int dual_ranging(clp_object* model, const int number_check, const int* which,
                           double* cost_increase, int* sequence_increase,
                           double* cost_decrease, int* sequence_decrease,
-                          double* value_increase, double* value_decrease);
+                          double* value_increase, double* value_decrease, clp_error* err);
 
 #ifdef __cplusplus
 }
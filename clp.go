@@ -17,7 +17,9 @@ value of a + b is 7.6, which is achieved when a = 2.4 and b = 5.2.  The example
 code associated with Simplex.LoadProblem shows how to set up and solve this
 precise problem using an API based directly on CLP's C++ API.  The example code
 associated with Simplex.EasyLoadDenseProblem shows how to specify the same
-problem using a more equation-oriented API specific to the clp package.
+problem using a more equation-oriented API specific to the clp package.  The
+example code associated with Model shows how to specify it algebraically, in
+terms of named variables and constraints.
 
 The clp package currently exposes only a tiny subset of the CLP library.
 
@@ -33,8 +35,10 @@ package clp
 
 // #cgo pkg-config: clp
 // #include <stdlib.h>
+// #include "clp-interface.h"
 import "C"
 import (
+	"fmt"
 	"reflect"
 	"unsafe"
 )
@@ -49,9 +53,18 @@ type Nonzero struct {
 // represents a variable, each row represents an expression, and each cell
 // containing a coefficient.  Bounds on rows and columns are applied during
 // model initialization.
+//
+// SparseData exports the matrix in compressed sparse column form: for each
+// column c, the row indices of its nonzero elements are
+// indices[starts[c]:starts[c]+lengths[c]], and the corresponding values are
+// the same range of elements.  Both starts and lengths must have one entry
+// per column.  Any type that implements Matrix can be passed to
+// Simplex.LoadProblem, although a *PackedMatrix is loaded without an
+// intermediate copy.
 type Matrix interface {
-	AppendColumn(col []Nonzero) // Append a column given values for all of its nonzero elements
-	Dims() (rows, cols int)     // Return the matrix's dimensions
+	AppendColumn(col []Nonzero)                                       // Append a column given values for all of its nonzero elements
+	Dims() (rows, cols int)                                           // Return the matrix's dimensions
+	SparseData() (starts, lengths, indices []int, elements []float64) // Return the matrix's nonzero elements in column order
 }
 
 // cMalloc asks C to allocate memory.  For convenience to Go, the arguments
@@ -71,6 +84,26 @@ func cFree(mem unsafe.Pointer) {
 	C.free(mem)
 }
 
+// cError converts a clp_error filled in by one of our C wrapper functions to
+// a Go error and frees its message.  It returns nil if the wrapper caught no
+// exception.  what names the Go method on whose behalf the wrapper was
+// called.
+func cError(what string, cErr *C.clp_error) error {
+	if cErr.code == C.CLP_OK {
+		return nil
+	}
+	msg := "unknown exception"
+	if cErr.message != nil {
+		msg = C.GoString(cErr.message)
+		C.free(unsafe.Pointer(cErr.message))
+		cErr.message = nil
+	}
+	if cErr.code == C.CLP_BAD_ALLOC {
+		return fmt.Errorf("%w: %s: %s", ErrOutOfMemory, what, msg)
+	}
+	return fmt.Errorf("%w: %s: %s", ErrException, what, msg)
+}
+
 // cSetArrayInt assigns a[i] = v where a is a C.int array allocated by
 // cMalloc and i and v are Go ints.
 func cSetArrayInt(a unsafe.Pointer, i, v int) {
@@ -103,6 +136,66 @@ func cGetArrayDouble(a unsafe.Pointer, i int) float64 {
 	return float64(*(*C.double)(ptr))
 }
 
+// cDoubleSlice returns a Go slice that aliases the n C doubles starting at a,
+// which lets whole vectors be moved between Go and C with copy instead of
+// element by element.  The slice must not be used after the C memory is
+// freed or reallocated.
+func cDoubleSlice(a unsafe.Pointer, n int) []float64 {
+	var s []float64
+	if a == nil || n == 0 {
+		return s
+	}
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&s))
+	hdr.Data = uintptr(a)
+	hdr.Len = n
+	hdr.Cap = n
+	return s
+}
+
+// cIntSlice is like cDoubleSlice but aliases an array of C ints.
+func cIntSlice(a unsafe.Pointer, n int) []C.int {
+	var s []C.int
+	if a == nil || n == 0 {
+		return s
+	}
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&s))
+	hdr.Data = uintptr(a)
+	hdr.Len = n
+	hdr.Cap = n
+	return s
+}
+
+// sizedFloats returns dst resliced to length n if its capacity suffices or a
+// newly allocated slice of length n otherwise.
+func sizedFloats(dst []float64, n int) []float64 {
+	if cap(dst) >= n {
+		return dst[:n]
+	}
+	return make([]float64, n)
+}
+
+// cStrings converts a slice of Go strings to a C array of C strings.  The
+// caller must free the result with cFreeStrings.
+func cStrings(strs []string) unsafe.Pointer {
+	arr := cMalloc(len(strs), (*C.char)(nil))
+	eSize := unsafe.Sizeof((*C.char)(nil))
+	for i, str := range strs {
+		ptr := unsafe.Pointer(uintptr(arr) + uintptr(i)*eSize)
+		*(**C.char)(ptr) = C.CString(str)
+	}
+	return arr
+}
+
+// cFreeStrings frees a C array of n C strings allocated by cStrings.
+func cFreeStrings(arr unsafe.Pointer, n int) {
+	eSize := unsafe.Sizeof((*C.char)(nil))
+	for i := 0; i < n; i++ {
+		ptr := unsafe.Pointer(uintptr(arr) + uintptr(i)*eSize)
+		cFree(unsafe.Pointer(*(**C.char)(ptr)))
+	}
+	cFree(arr)
+}
+
 // copyIntsGoC copies a slice of Go ints to a slice of C ints.
 func copyIntsGoC(cs []C.int, gs []int) {
 	if len(gs) != len(cs) {
@@ -0,0 +1,90 @@
+// Context-aware solves
+
+package clp
+
+// #include "clp-interface.h"
+import "C"
+import "context"
+
+// solveContext invokes a solve function with an event handler that stops the
+// solve once ctx is done and returns the solve's Result.  The solve function
+// returns an error only if CLP throws an exception, which solveContext passes
+// along.  Any handler installed with SetEventHandler is invoked as well and is
+// reinstalled when the solve finishes.  If ctx is already done, the solve is
+// not attempted.  A solve stopped because of ctx has status
+// StoppedByEventHandler and secondary status SecondaryStoppedByUser and
+// returns ctx.Err().
+func (s *Simplex) solveContext(ctx context.Context, solve func() (SimplexStatus, error)) (Result, error) {
+	if err := ctx.Err(); err != nil {
+		s.status = StoppedByEventHandler
+		s.solveTime = 0
+		s.solved = true
+		s.setSecondaryStatus(SecondaryStoppedByUser)
+		return s.Result(), err
+	}
+
+	// Install a handler that polls ctx.
+	user := s.eventHandler
+	done := ctx.Done()
+	cancelled := false
+	s.SetEventHandler(func(p Progress) bool {
+		select {
+		case <-done:
+			cancelled = true
+			return true
+		default:
+		}
+		return user != nil && user(p)
+	})
+	defer s.SetEventHandler(user)
+
+	// Perform the solve.
+	st, err := solve()
+	if err != nil {
+		return s.Result(), err
+	}
+	if cancelled && st == StoppedByEventHandler {
+		s.setSecondaryStatus(SecondaryStoppedByUser)
+		return s.Result(), ctx.Err()
+	}
+	return s.Result(), nil
+}
+
+// setSecondaryStatus overrides the secondary status reported by CLP.
+func (s *Simplex) setSecondaryStatus(st SecondaryStatus) {
+	var cErr C.clp_error
+	C.set_secondary_status(s.model, C.int(st), &cErr)
+	s.recordErr(cError("Simplex.setSecondaryStatus", &cErr))
+}
+
+// PrimalContext is like Primal but returns a Result describing the solve and
+// stops the solve early if ctx is cancelled or its deadline passes.  In that
+// case the Result has status StoppedByEventHandler and secondary status
+// SecondaryStoppedByUser, PrimalContext returns ctx.Err(), and the model
+// remains usable for subsequent solves.  If CLP throws an exception, the
+// Result has status StoppedOnErrors, and PrimalContext returns an error
+// describing the exception.
+func (s *Simplex) PrimalContext(ctx context.Context, vp ValuesPass, sfo StartFinishOptions) (Result, error) {
+	return s.solveContext(ctx, func() (SimplexStatus, error) { return s.primal(vp, sfo) })
+}
+
+// DualContext is like Dual but returns a Result describing the solve and
+// stops the solve early if ctx is cancelled or its deadline passes.  In that
+// case the Result has status StoppedByEventHandler and secondary status
+// SecondaryStoppedByUser, DualContext returns ctx.Err(), and the model
+// remains usable for subsequent solves.  If CLP throws an exception, the
+// Result has status StoppedOnErrors, and DualContext returns an error
+// describing the exception.
+func (s *Simplex) DualContext(ctx context.Context, vp ValuesPass, sfo StartFinishOptions) (Result, error) {
+	return s.solveContext(ctx, func() (SimplexStatus, error) { return s.dual(vp, sfo) })
+}
+
+// BarrierContext is like Barrier but returns a Result describing the solve
+// and stops the solve early if ctx is cancelled or its deadline passes.  CLP's
+// barrier method does not report progress, so ctx is checked only before the
+// solve starts and during crossover.  If CLP throws an exception, the Result
+// has status StoppedOnErrors, and BarrierContext returns an error describing
+// the exception.
+func (s *Simplex) BarrierContext(ctx context.Context, xover bool) (Result, error) {
+	return s.solveContext(ctx, func() (SimplexStatus, error) { return s.barrier(xover) })
+}
@@ -0,0 +1,73 @@
+// Test context-aware solves
+
+package clp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lanl/clp"
+)
+
+// Test if a solve with an already cancelled context returns immediately.
+func TestContextCancelled(t *testing.T) {
+	simp := transportProblem()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err := simp.DualContext(ctx, clp.NoValuesPass, clp.NoStartFinishOptions)
+	if err != context.Canceled {
+		t.Fatalf("Expected error %v but saw %v", context.Canceled, err)
+	}
+	if res.Status != clp.StoppedByEventHandler {
+		t.Fatalf("Expected status %d but saw %d", clp.StoppedByEventHandler, res.Status)
+	}
+	if res.Secondary != clp.SecondaryStoppedByUser {
+		t.Fatalf("Expected secondary status %d but saw %d", clp.SecondaryStoppedByUser, res.Secondary)
+	}
+	if sec := simp.SecondaryStatus(); sec != clp.SecondaryStoppedByUser {
+		t.Fatalf("Expected secondary status %d but saw %d", clp.SecondaryStoppedByUser, sec)
+	}
+
+	// An uncancelled context should let the solve run to completion.
+	res, err = simp.DualContext(context.Background(), clp.NoValuesPass, clp.NoStartFinishOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != clp.Optimal {
+		t.Fatalf("Expected status %d but saw %d", clp.Optimal, res.Status)
+	}
+}
+
+// Test if cancelling a context stops a solve in progress.
+func TestContextCancelMidSolve(t *testing.T) {
+	// Cancel the context from a user event handler, which should remain
+	// installed after the solve.
+	simp := transportProblem()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	simp.SetEventHandler(func(p clp.Progress) bool {
+		if p.Event == clp.EndOfIteration && p.Iteration >= 2 {
+			cancel()
+		}
+		return false
+	})
+	res, err := simp.PrimalContext(ctx, clp.NoValuesPass, clp.NoStartFinishOptions)
+	if err != context.Canceled {
+		t.Fatalf("Expected error %v but saw %v", context.Canceled, err)
+	}
+	if res.Status != clp.StoppedByEventHandler {
+		t.Fatalf("Expected status %d but saw %d", clp.StoppedByEventHandler, res.Status)
+	}
+	if sec := simp.SecondaryStatus(); sec != clp.SecondaryStoppedByUser {
+		t.Fatalf("Expected secondary status %d but saw %d", clp.SecondaryStoppedByUser, sec)
+	}
+	if simp.EventHandler() == nil {
+		t.Fatal("Expected the user's event handler to be reinstalled")
+	}
+
+	// The model should remain usable.
+	simp.SetEventHandler(nil)
+	if st := simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions); st != clp.Optimal {
+		t.Fatalf("Expected status %d but saw %d", clp.Optimal, st)
+	}
+}
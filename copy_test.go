@@ -0,0 +1,45 @@
+// Compare element-by-element and bulk copies across the cgo boundary
+
+package clp
+
+import (
+	"testing"
+	"unsafe"
+)
+
+// copyLen is the number of C doubles each copy benchmark moves.
+const copyLen = 100000
+
+// newCArray returns a C array of copyLen doubles, each equal to its index.
+func newCArray() unsafe.Pointer {
+	arr := newCArray()
+	for i := 0; i < copyLen; i++ {
+		cSetArrayDouble(arr, i, float64(i))
+	}
+	return arr
+}
+
+// Benchmark copying a C array into Go one element at a time, as the getters
+// did before they used cDoubleSlice.
+func BenchmarkCopyElementwise(b *testing.B) {
+	arr := newCArray()
+	defer cFree(arr)
+	dst := make([]float64, copyLen)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range dst {
+			dst[j] = cGetArrayDouble(arr, j)
+		}
+	}
+}
+
+// Benchmark copying a C array into Go in bulk.
+func BenchmarkCopyBulk(b *testing.B) {
+	arr := newCArray()
+	defer cFree(arr)
+	dst := make([]float64, copyLen)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(dst, cDoubleSlice(arr, copyLen))
+	}
+}
@@ -0,0 +1,44 @@
+// Error values
+
+package clp
+
+import "errors"
+
+// These are the categories of error returned by this package.  Errors are
+// generally wrapped with additional context, so use errors.Is to test for
+// them.
+var (
+	// ErrDimensionMismatch indicates that the length of an argument does
+	// not match the dimensions of the model or of another argument.
+	ErrDimensionMismatch = errors.New("clp: dimension mismatch")
+
+	// ErrIndexOutOfRange indicates that a row or column number lies
+	// outside the model.
+	ErrIndexOutOfRange = errors.New("clp: index out of range")
+
+	// ErrUnsupportedMatrix indicates that a Matrix implementation cannot
+	// be passed to CLP.
+	ErrUnsupportedMatrix = errors.New("clp: unsupported matrix type")
+
+	// ErrInvalidArgument indicates an argument that is malformed in some
+	// way other than its length.
+	ErrInvalidArgument = errors.New("clp: invalid argument")
+
+	// ErrIO indicates a failure to read or write a file.
+	ErrIO = errors.New("clp: I/O error")
+
+	// ErrNotOptimal indicates that an operation requires an optimal
+	// solution but the model has not been solved to optimality.
+	ErrNotOptimal = errors.New("clp: model is not optimal")
+
+	// ErrException indicates that CLP threw a C++ exception, typically a
+	// CoinError reporting invalid input.
+	ErrException = errors.New("clp: exception in CLP")
+
+	// ErrOutOfMemory indicates that CLP ran out of memory.
+	ErrOutOfMemory = errors.New("clp: out of memory")
+
+	// ErrInfeasibleOrUnbounded indicates that CLP determined that a
+	// problem has no optimal solution before attempting to solve it.
+	ErrInfeasibleOrUnbounded = errors.New("clp: problem is infeasible or unbounded")
+)
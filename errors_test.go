@@ -0,0 +1,173 @@
+// Test error reporting
+
+package clp_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lanl/clp"
+)
+
+// brokenMatrix is a Matrix implementation whose SparseData disagrees with
+// its dimensions.
+type brokenMatrix struct{}
+
+func (bm *brokenMatrix) AppendColumn(col []clp.Nonzero) {}
+
+func (bm *brokenMatrix) Dims() (rows, cols int) { return 1, 1 }
+
+func (bm *brokenMatrix) SparseData() (starts, lengths, indices []int, elements []float64) {
+	return nil, nil, nil, nil
+}
+
+// Test if TryLoadProblem reports typed errors.
+func TestTryLoadProblemErrors(t *testing.T) {
+	simp := clp.NewSimplex()
+	err := simp.TryLoadProblem(&brokenMatrix{}, nil, nil, nil, nil)
+	if !errors.Is(err, clp.ErrUnsupportedMatrix) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrUnsupportedMatrix, err)
+	}
+	mat := clp.NewPackedMatrix()
+	mat.AppendColumn([]clp.Nonzero{{Index: 0, Value: 1.0}})
+	err = simp.TryLoadProblem(mat, nil, []float64{1, 2}, nil, nil)
+	if !errors.Is(err, clp.ErrDimensionMismatch) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrDimensionMismatch, err)
+	}
+	if err = simp.TryLoadProblem(mat, nil, []float64{1}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The legacy method should still panic.
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected LoadProblem to panic")
+		}
+	}()
+	simp.LoadProblem(mat, nil, []float64{1, 2}, nil, nil)
+}
+
+// Test if TryEasyLoadDenseProblem reports typed errors.
+func TestTryEasyLoadDenseProblemErrors(t *testing.T) {
+	simp := clp.NewSimplex()
+	err := simp.TryEasyLoadDenseProblem(nil, nil, nil)
+	if !errors.Is(err, clp.ErrInvalidArgument) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrInvalidArgument, err)
+	}
+	err = simp.TryEasyLoadDenseProblem([]float64{1, 1}, nil, [][]float64{
+		{1, 1, 1, 5},
+		{0, 1, 5},
+	})
+	if !errors.Is(err, clp.ErrDimensionMismatch) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrDimensionMismatch, err)
+	}
+	err = simp.TryEasyLoadDenseProblem([]float64{1, 1}, [][2]float64{{0, 1}}, [][]float64{
+		{1, 1, 1, 5},
+	})
+	if !errors.Is(err, clp.ErrDimensionMismatch) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrDimensionMismatch, err)
+	}
+}
+
+// Test if TryWriteMPS and ReadMPS report I/O errors.
+func TestMPSIOErrors(t *testing.T) {
+	simp := smallProblem()
+	dir, err := os.MkdirTemp("", "clp-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bad := filepath.Join(dir, "no-such-dir", "model.mps")
+	if err = simp.TryWriteMPS(bad); !errors.Is(err, clp.ErrIO) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIO, err)
+	}
+	if err = simp.ReadMPS(bad); !errors.Is(err, clp.ErrIO) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIO, err)
+	}
+
+	// A file that exists but is malformed is not an I/O error.
+	garbled := filepath.Join(dir, "garbled.mps")
+	if err = os.WriteFile(garbled, []byte("NAME GARBLED\nROWS\n Q R0\nENDATA\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = simp.ReadMPS(garbled)
+	if !errors.Is(err, clp.ErrInvalidArgument) || errors.Is(err, clp.ErrIO) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrInvalidArgument, err)
+	}
+}
+
+// Test if the Try ranging methods report typed errors.
+func TestTryRangingErrors(t *testing.T) {
+	simp := smallProblem()
+	which := []int{0, 1}
+	n := len(which)
+	vi := make([]float64, n)
+	si := make([]int, n)
+	vd := make([]float64, n)
+	sd := make([]int, n)
+	if err := simp.TryPrimalRanging(n, which, vi, si, vd, sd); err != nil {
+		t.Fatal(err)
+	}
+	err := simp.TryPrimalRanging(n, which, vi, si, vd[:1], sd)
+	if !errors.Is(err, clp.ErrDimensionMismatch) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrDimensionMismatch, err)
+	}
+	ci := make([]float64, n)
+	cd := make([]float64, n)
+	if err = simp.TryDualRanging(n, which, ci, si, cd, sd, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	err = simp.TryDualRanging(n, which, ci, si, cd, sd, vi, vd[:1])
+	if !errors.Is(err, clp.ErrDimensionMismatch) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrDimensionMismatch, err)
+	}
+}
+
+// Test if the incremental-modification methods report typed errors.
+func TestModificationErrors(t *testing.T) {
+	simp := smallProblem()
+	err := simp.DeleteRows([]int{7})
+	if !errors.Is(err, clp.ErrIndexOutOfRange) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIndexOutOfRange, err)
+	}
+	err = simp.SetObjective([]float64{1})
+	if !errors.Is(err, clp.ErrDimensionMismatch) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrDimensionMismatch, err)
+	}
+}
+
+// Test if C++ exceptions thrown by CLP are reported as errors rather than
+// aborting the process.
+func TestCLPExceptions(t *testing.T) {
+	// CoinPackedMatrix throws a CoinError when asked to delete a
+	// nonexistent column.
+	mat := clp.NewPackedMatrix()
+	mat.AppendColumn([]clp.Nonzero{{Index: 0, Value: 1.0}})
+	mat.AppendColumn([]clp.Nonzero{{Index: 1, Value: 2.0}})
+	if err := mat.Err(); err != nil {
+		t.Fatal(err)
+	}
+	mat.DeleteColumns([]int{7})
+	if err := mat.Err(); !errors.Is(err, clp.ErrException) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrException, err)
+	}
+
+	// Deleting the same column twice makes CLP throw a CoinError, which
+	// should be returned directly.
+	simp := transportProblem()
+	err := simp.DeleteColumns([]int{3, 3})
+	if !errors.Is(err, clp.ErrException) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrException, err)
+	}
+	if err = simp.Err(); err != nil {
+		t.Fatalf("Expected no deferred error but saw %v", err)
+	}
+
+	// A fresh model should be unaffected.
+	simp = smallProblem()
+	if err = simp.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,102 @@
+// Automatic algorithm selection
+
+package clp
+
+// #include "clp-interface.h"
+import "C"
+import (
+	"fmt"
+	"time"
+)
+
+// A SolveType selects the algorithm InitialSolve uses.
+type SolveType int
+
+// These constants are the possible values for a SolveType.
+const (
+	SolveAutomatic      SolveType = 0 // Let CLP choose an algorithm based on the problem
+	SolveDual                     = 1 // Use the dual simplex method
+	SolvePrimal                   = 2 // Use the primal simplex method, possibly with sprint
+	SolveBarrier                  = 3 // Use the barrier method followed by crossover to a basic solution
+	SolveBarrierNoCross           = 4 // Use the barrier method without crossover
+)
+
+// String returns the name of a SolveType.
+func (st SolveType) String() string {
+	switch st {
+	case SolveAutomatic:
+		return "SolveAutomatic"
+	case SolveDual:
+		return "SolveDual"
+	case SolvePrimal:
+		return "SolvePrimal"
+	case SolveBarrier:
+		return "SolveBarrier"
+	case SolveBarrierNoCross:
+		return "SolveBarrierNoCross"
+	default:
+		return fmt.Sprintf("SolveType(%d)", int(st))
+	}
+}
+
+// SolveOptions configures InitialSolve.  The zero value matches the behavior
+// of CLP's standalone solver: CLP chooses the algorithm, presolve runs for
+// five passes, there is no crash, and CLP decides whether to use the idiot
+// and sprint heuristics.
+type SolveOptions struct {
+	Type           SolveType // Algorithm to use
+	PresolvePasses int       // Number of presolve passes: 0 for the default of 5, or negative to disable presolve
+	Crash          bool      // Whether to construct a starting basis with a crash procedure (SolveDual and SolvePrimal only); overrides IdiotPasses and SprintPasses
+	IdiotPasses    int       // Passes of the idiot heuristic, which finds an approximate starting point (SolveDual and SolvePrimal only): 0 to let CLP decide, or negative to disable the heuristic
+	SprintPasses   int       // Passes of the sprint heuristic, which solves a sequence of smaller problems (SolvePrimal only): 0 to let CLP decide, or negative to disable the heuristic; overrides IdiotPasses
+}
+
+// defaultPresolvePasses is the number of presolve passes CLP's standalone
+// solver performs.
+const defaultPresolvePasses = 5
+
+// InitialSolve solves a model from scratch with CLP's initialSolve method,
+// which can presolve the problem, construct a starting basis, and choose an
+// algorithm automatically.  It is the most convenient way to solve a problem
+// of unknown character.  InitialSolve returns a Result describing the solve.
+// It returns an error wrapping ErrInvalidArgument if opts is invalid and an
+// error wrapping ErrException, with a Result whose status is
+// StoppedOnErrors, if CLP throws an exception.
+func (s *Simplex) InitialSolve(opts SolveOptions) (Result, error) {
+	if opts.Type < SolveAutomatic || opts.Type > SolveBarrierNoCross {
+		return Result{}, fmt.Errorf("%w: Simplex.InitialSolve given unknown solve type %v", ErrInvalidArgument, opts.Type)
+	}
+
+	// Convert our zero-means-default conventions to those of the
+	// standalone solver, in which -1 means "let CLP decide" and 0 means
+	// "disable".
+	passes := opts.PresolvePasses
+	switch {
+	case passes == 0:
+		passes = defaultPresolvePasses
+	case passes < 0:
+		passes = 0
+	}
+	heuristic := func(n int) C.int {
+		switch {
+		case n == 0:
+			return -1
+		case n < 0:
+			return 0
+		default:
+			return C.int(n)
+		}
+	}
+	var crash C.int
+	if opts.Crash {
+		crash = 1
+	}
+
+	// Perform the solve.
+	var cErr C.clp_error
+	start := time.Now()
+	st := C.simplex_initial_solve(s.model, C.int(opts.Type), C.int(passes), crash,
+		heuristic(opts.IdiotPasses), heuristic(opts.SprintPasses), &cErr)
+	_, err := s.solveStatus("Simplex.InitialSolve", start, st, &cErr)
+	return s.Result(), err
+}
@@ -0,0 +1,48 @@
+// Test automatic algorithm selection
+
+package clp_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/lanl/clp"
+)
+
+// Test if InitialSolve finds the optimum with a variety of options.
+func TestInitialSolve(t *testing.T) {
+	exp := transportProblem()
+	exp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+	obj := exp.ObjectiveValue()
+	for _, opts := range []clp.SolveOptions{
+		{},
+		{Type: clp.SolveDual},
+		{Type: clp.SolveDual, Crash: true},
+		{Type: clp.SolvePrimal, PresolvePasses: -1},
+		{Type: clp.SolvePrimal, IdiotPasses: -1, SprintPasses: -1},
+		{Type: clp.SolvePrimal, SprintPasses: 3},
+		{Type: clp.SolveBarrier, PresolvePasses: 2},
+	} {
+		simp := transportProblem()
+		r, err := simp.InitialSolve(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Status != clp.Optimal {
+			t.Fatalf("Expected %v with %+v but saw %v", clp.Optimal, opts, r.Status)
+		}
+		if !closeTo(r.Objective, obj, 1e-6*math.Abs(obj)) {
+			t.Fatalf("Expected objective %v with %+v but saw %v", obj, opts, r.Objective)
+		}
+		if r.Objective != simp.ObjectiveValue() {
+			t.Fatalf("Expected the Result's objective %v to match the model's %v", r.Objective, simp.ObjectiveValue())
+		}
+	}
+
+	// An unknown solve type should be rejected.
+	_, err := clp.NewSimplex().InitialSolve(clp.SolveOptions{Type: 99})
+	if !errors.Is(err, clp.ErrInvalidArgument) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrInvalidArgument, err)
+	}
+}
@@ -0,0 +1,111 @@
+// Log redirection
+
+package clp
+
+// #include "clp-interface.h"
+import "C"
+import (
+	"fmt"
+	"io"
+)
+
+// A LogSeverity indicates the severity of a log message.  The values match
+// the severity characters CLP appends to its message numbers.
+type LogSeverity byte
+
+// These constants are the possible values for a LogSeverity.
+const (
+	LogInfo    LogSeverity = 'I' // Informational message
+	LogWarning             = 'W' // Warning
+	LogError               = 'E' // Error
+	LogSevere              = 'S' // Severe error
+)
+
+// String returns the name of a LogSeverity.
+func (ls LogSeverity) String() string {
+	switch ls {
+	case LogInfo:
+		return "info"
+	case LogWarning:
+		return "warning"
+	case LogError:
+		return "error"
+	case LogSevere:
+		return "severe"
+	default:
+		return fmt.Sprintf("LogSeverity(%q)", rune(ls))
+	}
+}
+
+// A LogMessage is a single message logged by CLP.
+type LogMessage struct {
+	Number   int         // Message number, as in "Clp0006I"
+	Severity LogSeverity // Severity of the message
+	Source   string      // Library that issued the message, such as "Clp" or "Coin"
+	Text     string      // Formatted message text, without a trailing newline
+}
+
+// A LogHandler receives log messages from a simplex model.  It runs on the
+// goroutine that called the method that logged the message.
+type LogHandler func(msg LogMessage)
+
+//export goLogCallback
+func goLogCallback(handle C.uintptr_t, number C.int, severity C.char, source, text *C.char) {
+	h, ok := lookupHandle(uintptr(handle)).(LogHandler)
+	if !ok {
+		return
+	}
+	h(LogMessage{
+		Number:   int(number),
+		Severity: LogSeverity(severity),
+		Source:   C.GoString(source),
+		Text:     C.GoString(text),
+	})
+}
+
+// SetLogLevel specifies how much a simplex model logs, from 0 (nothing, the
+// default) through 4 (verbose).  1 logs a summary of each solve, and 2 and
+// above log progress periodically.
+func (s *Simplex) SetLogLevel(level int) {
+	var cErr C.clp_error
+	C.simplex_set_log_level(s.model, C.int(level), &cErr)
+	s.recordErr(cError("Simplex.SetLogLevel", &cErr))
+}
+
+// LogLevel returns how much a simplex model logs.
+func (s *Simplex) LogLevel() int {
+	var cErr C.clp_error
+	level := C.simplex_get_log_level(s.model, &cErr)
+	s.recordErr(cError("Simplex.LogLevel", &cErr))
+	return int(level)
+}
+
+// SetLogHandler sends each message a simplex model logs to a Go function
+// instead of to standard output.  Each model has its own handler, so
+// concurrent solves do not mix their logs.  Passing nil restores logging to
+// standard output.  Messages are logged only if SetLogLevel has been given a
+// positive level.
+func (s *Simplex) SetLogHandler(h LogHandler) {
+	old := s.logHandle
+	s.logHandle = 0
+	if h != nil {
+		s.logHandle = registerHandle(h)
+	}
+	var cErr C.clp_error
+	C.simplex_set_log_handler(s.model, C.uintptr_t(s.logHandle), &cErr)
+	s.recordErr(cError("Simplex.SetLogHandler", &cErr))
+	unregisterHandle(old)
+}
+
+// SetLogWriter writes each message a simplex model logs as a line of text to
+// an io.Writer instead of to standard output.  Write errors are ignored.
+// Passing nil restores logging to standard output.
+func (s *Simplex) SetLogWriter(w io.Writer) {
+	if w == nil {
+		s.SetLogHandler(nil)
+		return
+	}
+	s.SetLogHandler(func(msg LogMessage) {
+		fmt.Fprintln(w, msg.Text)
+	})
+}
@@ -0,0 +1,85 @@
+// Test log redirection
+
+package clp_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lanl/clp"
+)
+
+// Test if we can capture a model's log in an io.Writer.
+func TestLogWriter(t *testing.T) {
+	simp := smallProblem()
+	if lvl := simp.LogLevel(); lvl != 0 {
+		t.Fatalf("Expected a default log level of 0 but saw %d", lvl)
+	}
+	var buf bytes.Buffer
+	simp.SetLogWriter(&buf)
+	simp.SetLogLevel(1)
+	if lvl := simp.LogLevel(); lvl != 1 {
+		t.Fatalf("Expected log level 1 but saw %d", lvl)
+	}
+	simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+	if !strings.Contains(buf.String(), "Optimal") {
+		t.Fatalf("Expected the log to mention an optimal solution but saw %q", buf.String())
+	}
+
+	// Ensure that nothing is logged at level 0.
+	buf.Reset()
+	simp.SetLogLevel(0)
+	simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+	if buf.Len() != 0 {
+		t.Fatalf("Expected no log output at level 0 but saw %q", buf.String())
+	}
+}
+
+// Test if a log handler receives structured messages.
+func TestLogHandler(t *testing.T) {
+	simp := transportProblem()
+	var msgs []clp.LogMessage
+	simp.SetLogHandler(func(msg clp.LogMessage) {
+		msgs = append(msgs, msg)
+	})
+	simp.SetLogLevel(1)
+	simp.Dual(clp.NoValuesPass, clp.NoStartFinishOptions)
+	if len(msgs) == 0 {
+		t.Fatal("Log handler received no messages")
+	}
+	for _, msg := range msgs {
+		if msg.Source == "" || msg.Text == "" {
+			t.Fatalf("Received an incomplete log message %+v", msg)
+		}
+		switch msg.Severity {
+		case clp.LogInfo, clp.LogWarning, clp.LogError, clp.LogSevere:
+		default:
+			t.Fatalf("Received a log message with unexpected severity %v", msg.Severity)
+		}
+	}
+}
+
+// Test if concurrent solves keep their logs separate.
+func TestLogConcurrent(t *testing.T) {
+	const nModels = 4
+	bufs := make([]bytes.Buffer, nModels)
+	var wg sync.WaitGroup
+	for i := range bufs {
+		wg.Add(1)
+		go func(buf *bytes.Buffer) {
+			defer wg.Done()
+			simp := transportProblem()
+			simp.SetLogWriter(buf)
+			simp.SetLogLevel(1)
+			simp.Dual(clp.NoValuesPass, clp.NoStartFinishOptions)
+		}(&bufs[i])
+	}
+	wg.Wait()
+	for i := range bufs {
+		if !strings.Contains(bufs[i].String(), "Optimal") {
+			t.Fatalf("Expected log %d to mention an optimal solution but saw %q", i, bufs[i].String())
+		}
+	}
+}
@@ -0,0 +1,909 @@
+// CPLEX LP-format input and output
+
+package clp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// An LPError describes a failure to parse a CPLEX LP file.  It wraps
+// ErrInvalidArgument.
+type LPError struct {
+	Line int    // One-based line number at which the error was detected
+	Msg  string // Description of the error
+}
+
+// Error returns an LPError as a string.
+func (e *LPError) Error() string {
+	return fmt.Sprintf("clp: LP line %d: %s", e.Line, e.Msg)
+}
+
+// Unwrap returns ErrInvalidArgument.
+func (e *LPError) Unwrap() error {
+	return ErrInvalidArgument
+}
+
+// An lpTokenKind indicates the lexical category of an lpToken.
+type lpTokenKind int
+
+// These constants are the possible values for an lpTokenKind.
+const (
+	lpEOF      lpTokenKind = iota // End of input
+	lpSection                     // Section keyword such as "Subject To"
+	lpNumber                      // Unsigned numeric constant, including infinity
+	lpName                        // Variable or constraint name
+	lpSign                        // "+" or "-"
+	lpRelation                    // "<=", ">=", or "="
+	lpColon                       // ":" following a constraint name
+)
+
+// An lpToken is a single lexical element of an LP file.
+type lpToken struct {
+	kind  lpTokenKind // Lexical category
+	text  string      // Name, canonical section keyword, or canonical relation
+	value float64     // Numeric value or +1/-1 for a sign
+	line  int         // One-based line number
+}
+
+// String describes a token for use in error messages.
+func (t lpToken) String() string {
+	switch t.kind {
+	case lpEOF:
+		return "end of file"
+	case lpSection:
+		return fmt.Sprintf("section keyword %q", t.text)
+	case lpNumber:
+		return fmt.Sprintf("number %v", t.value)
+	case lpName:
+		return fmt.Sprintf("name %q", t.text)
+	case lpSign:
+		if t.value < 0 {
+			return `"-"`
+		}
+		return `"+"`
+	case lpRelation:
+		return fmt.Sprintf("relation %q", t.text)
+	case lpColon:
+		return `":"`
+	default:
+		panic("clp: internal error: unknown LP token kind")
+	}
+}
+
+// lpSections maps each lowercase section keyword, which may be two words
+// long, to a canonical name.
+var lpSections = map[string]string{
+	"minimize":        "min",
+	"minimise":        "min",
+	"minimum":         "min",
+	"min":             "min",
+	"maximize":        "max",
+	"maximise":        "max",
+	"maximum":         "max",
+	"max":             "max",
+	"subject to":      "st",
+	"such that":       "st",
+	"st":              "st",
+	"s.t.":            "st",
+	"st.":             "st",
+	"bounds":          "bounds",
+	"bound":           "bounds",
+	"general":         "general",
+	"generals":        "general",
+	"gen":             "general",
+	"binary":          "binary",
+	"binaries":        "binary",
+	"bin":             "binary",
+	"end":             "end",
+	"semi-continuous": "",
+	"semis":           "",
+	"semi":            "",
+	"sos":             "",
+}
+
+// isLPNameChar says whether a character can appear in an LP name.
+func isLPNameChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("!\"#$%&()/,.;?@_`'{}[]|~", r)
+}
+
+// lexLP splits an LP file into tokens.
+func lexLP(r io.Reader) ([]lpToken, error) {
+	toks := make([]lpToken, 0, 1024)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '\\'); i >= 0 {
+			line = line[:i] // Discard comments.
+		}
+
+		// Section keywords are recognized only at the start of a line.
+		words := strings.Fields(strings.ToLower(line))
+		if len(words) > 0 {
+			nWords := 0
+			canon, ok := "", false
+			if len(words) > 1 {
+				canon, ok = lpSections[words[0]+" "+words[1]]
+				nWords = 2
+			}
+			if !ok {
+				canon, ok = lpSections[words[0]]
+				nWords = 1
+			}
+			if ok {
+				if canon == "" {
+					return nil, &LPError{Line: lineNum, Msg: fmt.Sprintf("unsupported section %q", words[0])}
+				}
+				toks = append(toks, lpToken{kind: lpSection, text: canon, line: lineNum})
+				for ; nWords > 0; nWords-- {
+					line = strings.TrimLeftFunc(line, unicode.IsSpace)
+					line = strings.TrimLeftFunc(line, func(r rune) bool { return !unicode.IsSpace(r) })
+				}
+			}
+		}
+
+		// Tokenize the rest of the line.
+		for i := 0; i < len(line); {
+			c := line[i]
+			switch {
+			case c == ' ' || c == '\t' || c == '\r':
+				i++
+			case c == '+' || c == '-':
+				v := 1.0
+				if c == '-' {
+					v = -1.0
+				}
+				toks = append(toks, lpToken{kind: lpSign, value: v, line: lineNum})
+				i++
+			case c == '<' || c == '>' || c == '=':
+				// Canonicalize <, =<, >, and => to <= and >=.
+				rel := "="
+				i++
+				switch {
+				case c != '=':
+					rel = string(c) + "="
+					if i < len(line) && line[i] == '=' {
+						i++
+					}
+				case i < len(line) && (line[i] == '<' || line[i] == '>'):
+					rel = string(line[i]) + "="
+					i++
+				}
+				toks = append(toks, lpToken{kind: lpRelation, text: rel, line: lineNum})
+			case c == ':':
+				toks = append(toks, lpToken{kind: lpColon, line: lineNum})
+				i++
+			case (c >= '0' && c <= '9') || (c == '.' && i+1 < len(line) && line[i+1] >= '0' && line[i+1] <= '9'):
+				j := i
+				for j < len(line) && ((line[j] >= '0' && line[j] <= '9') || line[j] == '.') {
+					j++
+				}
+				if j < len(line) && (line[j] == 'e' || line[j] == 'E') {
+					k := j + 1
+					if k < len(line) && (line[k] == '+' || line[k] == '-') {
+						k++
+					}
+					if k < len(line) && line[k] >= '0' && line[k] <= '9' {
+						for k < len(line) && line[k] >= '0' && line[k] <= '9' {
+							k++
+						}
+						j = k
+					}
+				}
+				v, err := strconv.ParseFloat(line[i:j], 64)
+				if err != nil {
+					return nil, &LPError{Line: lineNum, Msg: fmt.Sprintf("malformed number %q", line[i:j])}
+				}
+				toks = append(toks, lpToken{kind: lpNumber, value: v, line: lineNum})
+				i = j
+			default:
+				j := i
+				for j < len(line) {
+					r, w := utf8.DecodeRuneInString(line[j:])
+					if !isLPNameChar(r) {
+						break
+					}
+					j += w
+				}
+				if j == i {
+					r, _ := utf8.DecodeRuneInString(line[i:])
+					return nil, &LPError{Line: lineNum, Msg: fmt.Sprintf("unexpected character %q", r)}
+				}
+				name := line[i:j]
+				switch strings.ToLower(name) {
+				case "inf", "infinity":
+					toks = append(toks, lpToken{kind: lpNumber, value: math.Inf(1), line: lineNum})
+				default:
+					toks = append(toks, lpToken{kind: lpName, text: name, line: lineNum})
+				}
+				i = j
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	toks = append(toks, lpToken{kind: lpEOF, line: lineNum})
+	return toks, nil
+}
+
+// An lpParser maintains state while parsing an LP file.
+type lpParser struct {
+	toks     []lpToken      // Tokens to parse
+	pos      int            // Index of the current token
+	sense    OptDirection   // Objective sense
+	offset   float64        // Constant term in the objective function
+	colIdx   map[string]int // Map from column name to column index
+	colNames []string       // Column names in order of first appearance
+	cols     [][]Nonzero    // Column contents
+	obj      []float64      // Objective-function coefficients
+	cb       []Bounds       // Column bounds
+	isInt    []bool         // Whether each column is integer-valued
+	rowIdx   map[string]int // Map from row name to row index
+	rowNames []string       // Row names
+	rb       []Bounds       // Row bounds
+}
+
+// An lpExpr is a parsed linear expression.
+type lpExpr struct {
+	terms    []Nonzero // Column index and coefficient of each term
+	constant float64   // Sum of all constant terms
+}
+
+// tok returns the current token.
+func (p *lpParser) tok() lpToken {
+	return p.toks[p.pos]
+}
+
+// peek returns the token following the current token.
+func (p *lpParser) peek() lpToken {
+	if p.pos+1 < len(p.toks) {
+		return p.toks[p.pos+1]
+	}
+	return p.toks[len(p.toks)-1]
+}
+
+// advance moves to the next token.
+func (p *lpParser) advance() {
+	if p.pos < len(p.toks)-1 {
+		p.pos++
+	}
+}
+
+// errorf returns an LPError associated with the current token.
+func (p *lpParser) errorf(format string, a ...interface{}) error {
+	return &LPError{Line: p.tok().line, Msg: fmt.Sprintf(format, a...)}
+}
+
+// atLabel says whether the current token begins a "name:" label.
+func (p *lpParser) atLabel() bool {
+	return p.tok().kind == lpName && p.peek().kind == lpColon
+}
+
+// column returns the index of the named column, creating the column if
+// necessary.
+func (p *lpParser) column(name string) int {
+	if c, ok := p.colIdx[name]; ok {
+		return c
+	}
+	c := len(p.colNames)
+	p.colIdx[name] = c
+	p.colNames = append(p.colNames, name)
+	p.cols = append(p.cols, nil)
+	p.obj = append(p.obj, 0.0)
+	p.cb = append(p.cb, Bounds{Lower: 0.0, Upper: math.Inf(1)})
+	p.isInt = append(p.isInt, false)
+	return c
+}
+
+// parseSignedNumber parses a number preceded by zero or more signs.
+func (p *lpParser) parseSignedNumber() (float64, error) {
+	sign := 1.0
+	for p.tok().kind == lpSign {
+		sign *= p.tok().value
+		p.advance()
+	}
+	if p.tok().kind != lpNumber {
+		return 0.0, p.errorf("expected a number but saw %v", p.tok())
+	}
+	v := sign * p.tok().value
+	p.advance()
+	return v, nil
+}
+
+// parseRelation parses a relational operator.
+func (p *lpParser) parseRelation() (string, error) {
+	if p.tok().kind != lpRelation {
+		return "", p.errorf("expected <=, >=, or = but saw %v", p.tok())
+	}
+	rel := p.tok().text
+	p.advance()
+	return rel, nil
+}
+
+// atSignedNumberRelation says whether the current token begins a signed
+// number followed by a relational operator, as in "-5 <= x".
+func (p *lpParser) atSignedNumberRelation() bool {
+	i := p.pos
+	for p.toks[i].kind == lpSign {
+		i++
+	}
+	return p.toks[i].kind == lpNumber && p.toks[i+1].kind == lpRelation
+}
+
+// parseExpr parses a linear expression.  Coefficients of repeated variables
+// are summed.
+func (p *lpParser) parseExpr() (lpExpr, error) {
+	var e lpExpr
+	where := make(map[int]int) // Map from column to index into e.terms
+	for first := true; ; first = false {
+		// All terms but the first must begin with a sign.
+		sign, sawSign := 1.0, false
+		for p.tok().kind == lpSign {
+			sign *= p.tok().value
+			sawSign = true
+			p.advance()
+		}
+		if !first && !sawSign {
+			return e, nil
+		}
+
+		// Parse a coefficient, a variable, or both.
+		coeff, sawCoeff := sign, false
+		if p.tok().kind == lpNumber {
+			coeff *= p.tok().value
+			sawCoeff = true
+			p.advance()
+		}
+		if p.tok().kind != lpName || p.atLabel() {
+			switch {
+			case sawCoeff:
+				e.constant += coeff
+				continue
+			case sawSign:
+				return e, p.errorf("expected a term after the sign but saw %v", p.tok())
+			default:
+				return e, nil // Empty expression
+			}
+		}
+		col := p.column(p.tok().text)
+		p.advance()
+		if i, ok := where[col]; ok {
+			e.terms[i].Value += coeff
+		} else {
+			where[col] = len(e.terms)
+			e.terms = append(e.terms, Nonzero{Index: col, Value: coeff})
+		}
+	}
+}
+
+// parseObjective parses the objective function, which may be empty.
+func (p *lpParser) parseObjective() error {
+	if p.atLabel() {
+		p.advance()
+		p.advance()
+	}
+	e, err := p.parseExpr()
+	if err != nil {
+		return err
+	}
+	for _, t := range e.terms {
+		p.obj[t.Index] += t.Value
+	}
+	p.offset += e.constant
+	return nil
+}
+
+// parseConstraint parses a single constraint of the form "[name:] expr rel
+// rhs", "[name:] lhs rel expr", or "[name:] lhs rel expr rel rhs".  Unnamed
+// constraints are given CLP-style names.
+func (p *lpParser) parseConstraint() error {
+	// Parse the optional constraint name.
+	line := p.tok().line
+	name := fmt.Sprintf("R%07d", len(p.rowNames))
+	if p.atLabel() {
+		name = p.tok().text
+		p.advance()
+		p.advance()
+	}
+	if _, dup := p.rowIdx[name]; dup {
+		return &LPError{Line: line, Msg: fmt.Sprintf("duplicate constraint name %q", name)}
+	}
+
+	// Parse the constraint itself.
+	var b Bounds
+	var e lpExpr
+	var err error
+	if p.atSignedNumberRelation() {
+		// Constraint written with the constant first, "lhs rel expr [rel
+		// rhs]"
+		var lhs float64
+		var rel1 string
+		if lhs, err = p.parseSignedNumber(); err != nil {
+			return err
+		}
+		if rel1, err = p.parseRelation(); err != nil {
+			return err
+		}
+		if e, err = p.parseExpr(); err != nil {
+			return err
+		}
+		if p.tok().kind == lpRelation {
+			// Ranged constraint
+			rel2, _ := p.parseRelation()
+			rhs, err := p.parseSignedNumber()
+			if err != nil {
+				return err
+			}
+			switch {
+			case rel1 == "<=" && rel2 == "<=":
+				b = Bounds{Lower: lhs, Upper: rhs}
+			case rel1 == ">=" && rel2 == ">=":
+				b = Bounds{Lower: rhs, Upper: lhs}
+			default:
+				return &LPError{Line: line, Msg: fmt.Sprintf("inconsistent relations %s and %s in ranged constraint %q", rel1, rel2, name)}
+			}
+		} else {
+			// Single-sided or equality constraint
+			switch rel1 {
+			case "<=":
+				b = Bounds{Lower: lhs, Upper: math.Inf(1)}
+			case ">=":
+				b = Bounds{Lower: math.Inf(-1), Upper: lhs}
+			default:
+				b = Bounds{Lower: lhs, Upper: lhs}
+			}
+		}
+	} else {
+		// Constraint written with the constant last, "expr rel rhs"
+		var rel string
+		var rhs float64
+		if e, err = p.parseExpr(); err != nil {
+			return err
+		}
+		if rel, err = p.parseRelation(); err != nil {
+			return err
+		}
+		if rhs, err = p.parseSignedNumber(); err != nil {
+			return err
+		}
+		switch rel {
+		case "<=":
+			b = Bounds{Lower: math.Inf(-1), Upper: rhs}
+		case ">=":
+			b = Bounds{Lower: rhs, Upper: math.Inf(1)}
+		default:
+			b = Bounds{Lower: rhs, Upper: rhs}
+		}
+	}
+	b.Lower -= e.constant
+	b.Upper -= e.constant
+
+	// Add the constraint to the model.
+	row := len(p.rowNames)
+	p.rowIdx[name] = row
+	p.rowNames = append(p.rowNames, name)
+	p.rb = append(p.rb, b)
+	for _, t := range e.terms {
+		if t.Value != 0.0 {
+			p.cols[t.Index] = append(p.cols[t.Index], Nonzero{Index: row, Value: t.Value})
+		}
+	}
+	return nil
+}
+
+// parseBound parses a single entry in the Bounds section.
+func (p *lpParser) parseBound() error {
+	// Handle "x free".
+	if p.tok().kind == lpName && p.peek().kind == lpName && strings.EqualFold(p.peek().text, "free") {
+		col := p.column(p.tok().text)
+		p.cb[col] = Bounds{Lower: math.Inf(-1), Upper: math.Inf(1)}
+		p.advance()
+		p.advance()
+		return nil
+	}
+
+	// Handle "lb <= x [<= ub]" and its variations.
+	if p.atSignedNumberRelation() {
+		v, _ := p.parseSignedNumber()
+		rel, _ := p.parseRelation()
+		if p.tok().kind != lpName {
+			return p.errorf("expected a variable name in a bound but saw %v", p.tok())
+		}
+		col := p.column(p.tok().text)
+		p.advance()
+		if err := p.applyBound(col, rel, v, true); err != nil {
+			return err
+		}
+		if p.tok().kind != lpRelation {
+			return nil
+		}
+		rel, _ = p.parseRelation()
+		v, err := p.parseSignedNumber()
+		if err != nil {
+			return err
+		}
+		return p.applyBound(col, rel, v, false)
+	}
+
+	// Handle "x <= ub" and its variations.
+	if p.tok().kind != lpName {
+		return p.errorf("expected a bound but saw %v", p.tok())
+	}
+	col := p.column(p.tok().text)
+	p.advance()
+	rel, err := p.parseRelation()
+	if err != nil {
+		return err
+	}
+	v, err := p.parseSignedNumber()
+	if err != nil {
+		return err
+	}
+	return p.applyBound(col, rel, v, false)
+}
+
+// applyBound applies a relation to a column's bounds.  If numFirst is true,
+// the relation was written "v rel x" rather than "x rel v".
+func (p *lpParser) applyBound(col int, rel string, v float64, numFirst bool) error {
+	if numFirst {
+		switch rel {
+		case "<=":
+			rel = ">="
+		case ">=":
+			rel = "<="
+		}
+	}
+	switch rel {
+	case "<=":
+		p.cb[col].Upper = v
+	case ">=":
+		p.cb[col].Lower = v
+	default:
+		p.cb[col] = Bounds{Lower: v, Upper: v}
+	}
+	if p.cb[col].Lower == math.Inf(1) || p.cb[col].Upper == math.Inf(-1) {
+		return p.errorf("infeasible infinite bound on variable %q", p.colNames[col])
+	}
+	return nil
+}
+
+// parseIntegers parses a list of variable names in a General or Binary
+// section.
+func (p *lpParser) parseIntegers(binary bool) {
+	for p.tok().kind == lpName {
+		col := p.column(p.tok().text)
+		p.isInt[col] = true
+		if binary {
+			p.cb[col] = Bounds{Lower: 0.0, Upper: 1.0}
+		}
+		p.advance()
+	}
+}
+
+// parse parses an entire LP file.
+func (p *lpParser) parse() error {
+	// The file must begin with the objective sense.
+	t := p.tok()
+	if t.kind != lpSection || (t.text != "min" && t.text != "max") {
+		return p.errorf("expected Minimize or Maximize but saw %v", t)
+	}
+	p.sense = Minimize
+	if t.text == "max" {
+		p.sense = Maximize
+	}
+	p.advance()
+	if err := p.parseObjective(); err != nil {
+		return err
+	}
+
+	// Process each section in turn.
+	for {
+		t = p.tok()
+		switch {
+		case t.kind == lpEOF:
+			return nil
+		case t.kind != lpSection:
+			return p.errorf("unexpected %v", t)
+		}
+		p.advance()
+		switch t.text {
+		case "min", "max":
+			return &LPError{Line: t.line, Msg: "multiple objective functions"}
+		case "st":
+			for p.tok().kind != lpSection && p.tok().kind != lpEOF {
+				if err := p.parseConstraint(); err != nil {
+					return err
+				}
+			}
+		case "bounds":
+			for p.tok().kind != lpSection && p.tok().kind != lpEOF {
+				if err := p.parseBound(); err != nil {
+					return err
+				}
+			}
+		case "general", "binary":
+			p.parseIntegers(t.text == "binary")
+		case "end":
+			return nil
+		}
+	}
+}
+
+// ReadLP replaces the model with one read in CPLEX LP format.  ReadLP
+// supports the objective sense and objective function (including a constant
+// term), named or anonymous constraints (including ranged constraints written
+// as "lhs <= expression <= rhs"), a Bounds section (including "free"
+// variables and infinite bounds), and General and Binary sections.  Variables
+// declared in the latter two sections are marked as integer-valued, although
+// CLP itself solves only the linear relaxation.  Any parse failure is
+// reported as an *LPError.  If the parsed problem cannot be loaded, ReadLP
+// returns the error from TryLoadProblem.
+func (s *Simplex) ReadLP(r io.Reader) error {
+	// Parse the input.
+	toks, err := lexLP(r)
+	if err != nil {
+		return err
+	}
+	p := &lpParser{
+		toks:   toks,
+		colIdx: make(map[string]int),
+		rowIdx: make(map[string]int),
+	}
+	if err := p.parse(); err != nil {
+		return err
+	}
+
+	// Load the parsed problem into the model.
+	mat := NewPackedMatrix()
+	for _, col := range p.cols {
+		mat.AppendColumn(col)
+	}
+	mat.SetDimensions(len(p.rb), len(p.cols))
+	if err := s.TryLoadProblem(mat, p.cb, p.obj, p.rb, nil); err != nil {
+		return err
+	}
+	s.SetOptimizationDirection(p.sense)
+	s.SetObjectiveOffset(-p.offset)
+	if err := s.SetRowNames(p.rowNames); err != nil {
+		return err
+	}
+	if err := s.SetColumnNames(p.colNames); err != nil {
+		return err
+	}
+	for i, isInt := range p.isInt {
+		if isInt {
+			s.setInteger(i)
+		}
+	}
+	return nil
+}
+
+// formatLPNumber formats a number for output in LP format.
+func formatLPNumber(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "inf"
+	case math.IsInf(v, -1):
+		return "-inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// formatLPTerms formats a linear expression for output in LP format, breaking lines
+// periodically to keep them readable.
+func formatLPTerms(terms []Nonzero, names []string) string {
+	var sb strings.Builder
+	for i, t := range terms {
+		if i > 0 && i%8 == 0 {
+			sb.WriteString("\n   ")
+		}
+		switch {
+		case i == 0 && t.Value < 0:
+			sb.WriteString("-")
+		case i > 0 && t.Value < 0:
+			sb.WriteString(" - ")
+		case i > 0:
+			sb.WriteString(" + ")
+		}
+		if a := math.Abs(t.Value); a != 1.0 {
+			sb.WriteString(formatLPNumber(a))
+			sb.WriteString(" ")
+		}
+		sb.WriteString(names[t.Index])
+	}
+	return sb.String()
+}
+
+// WriteLP writes the model in CPLEX LP format.  Ranged constraints are
+// written as "lhs <= expression <= rhs", which ReadLP accepts but some other
+// LP readers may not.  Names are written as is, so models whose row or column
+// names contain spaces or other characters that LP format does not allow will
+// not read back correctly.
+func (s *Simplex) WriteLP(w io.Writer) error {
+	// Read the model through the same accessors WriteMPS uses.
+	nr, nc := s.Dims()
+	obj := s.Objective()
+	cb := s.ColumnBounds()
+	rb := s.RowBounds()
+	rowNames := s.RowNames()
+	colNames := s.ColumnNames()
+	isInt := make([]bool, nc)
+	for i := range isInt {
+		isInt[i] = s.isInteger(i)
+	}
+
+	// Transpose the column-ordered constraint matrix into rows.
+	rows := make([][]Nonzero, nr)
+	used := make([]bool, nc)
+	starts, lengths, indices, elements := s.Matrix().SparseData()
+	for c, st := range starts {
+		for i := st; i < st+lengths[c]; i++ {
+			r := indices[i]
+			rows[r] = append(rows[r], Nonzero{Index: c, Value: elements[i]})
+			used[c] = true
+		}
+	}
+
+	// Determine which columns need an explicit entry in the Bounds
+	// section.  We omit the default bounds of [0, ∞) except on variables
+	// that would otherwise not appear at all.
+	bounds := make([]string, nc)
+	binary := make([]bool, nc)
+	for c, b := range cb {
+		name := colNames[c]
+		switch {
+		case isInt[c] && b.Lower == 0.0 && b.Upper == 1.0:
+			binary[c] = true
+		case b.Lower == b.Upper:
+			bounds[c] = fmt.Sprintf("%s = %s", name, formatLPNumber(b.Lower))
+		case math.IsInf(b.Lower, -1) && math.IsInf(b.Upper, 1):
+			bounds[c] = fmt.Sprintf("%s free", name)
+		case b.Lower == 0.0 && math.IsInf(b.Upper, 1):
+			if !used[c] && obj[c] == 0.0 && !isInt[c] {
+				bounds[c] = fmt.Sprintf("%s >= 0", name)
+			}
+		case b.Lower == 0.0:
+			bounds[c] = fmt.Sprintf("%s <= %s", name, formatLPNumber(b.Upper))
+		case math.IsInf(b.Upper, 1):
+			bounds[c] = fmt.Sprintf("%s >= %s", name, formatLPNumber(b.Lower))
+		default:
+			bounds[c] = fmt.Sprintf("%s <= %s <= %s", formatLPNumber(b.Lower), name, formatLPNumber(b.Upper))
+		}
+	}
+
+	// ReadLP numbers columns in order of first appearance.  To preserve
+	// the column order, we list every column up to the last one that
+	// would otherwise be renumbered in the objective function, with a zero
+	// coefficient if necessary.
+	order := make([]int, 0, nc)
+	seen := make([]bool, nc)
+	appear := func(c int) {
+		if !seen[c] {
+			seen[c] = true
+			order = append(order, c)
+		}
+	}
+	for c, v := range obj {
+		if v != 0.0 {
+			appear(c)
+		}
+	}
+	for _, row := range rows {
+		if len(row) == 0 && nc > 0 {
+			appear(0) // Empty rows are written as "0 <first column>".
+		}
+		for _, t := range row {
+			appear(t.Index)
+		}
+	}
+	for c := range bounds {
+		if bounds[c] != "" {
+			appear(c)
+		}
+	}
+	for _, bin := range [...]bool{false, true} {
+		for c := range binary {
+			if isInt[c] && binary[c] == bin {
+				appear(c)
+			}
+		}
+	}
+	lastMoved := -1
+	for i, c := range order {
+		if i != c && c > lastMoved {
+			lastMoved = c
+		}
+	}
+	objTerms := make([]Nonzero, 0, nc)
+	for c, v := range obj {
+		if v != 0.0 || c <= lastMoved {
+			objTerms = append(objTerms, Nonzero{Index: c, Value: v})
+		}
+	}
+
+	// Write the objective function.
+	bw := bufio.NewWriter(w)
+	if s.OptimizationDirection() == Maximize {
+		fmt.Fprintln(bw, "Maximize")
+	} else {
+		fmt.Fprintln(bw, "Minimize")
+	}
+	objStr := formatLPTerms(objTerms, colNames)
+	switch k := -s.ObjectiveOffset(); {
+	case objStr == "" && k == 0.0:
+		objStr = "0"
+	case objStr == "":
+		objStr = formatLPNumber(k)
+	case k > 0:
+		objStr += " + " + formatLPNumber(k)
+	case k < 0:
+		objStr += " - " + formatLPNumber(-k)
+	}
+	fmt.Fprintf(bw, " obj: %s\n", objStr)
+
+	// Write the constraints.
+	fmt.Fprintln(bw, "Subject To")
+	for r, row := range rows {
+		expr := formatLPTerms(row, colNames)
+		switch {
+		case expr != "":
+		case nc > 0:
+			expr = "0 " + colNames[0]
+		default:
+			expr = "0"
+		}
+		b := rb[r]
+		switch {
+		case b.Lower == b.Upper:
+			fmt.Fprintf(bw, " %s: %s = %s\n", rowNames[r], expr, formatLPNumber(b.Upper))
+		case math.IsInf(b.Lower, -1) && !math.IsInf(b.Upper, 1):
+			fmt.Fprintf(bw, " %s: %s <= %s\n", rowNames[r], expr, formatLPNumber(b.Upper))
+		case math.IsInf(b.Upper, 1):
+			fmt.Fprintf(bw, " %s: %s >= %s\n", rowNames[r], expr, formatLPNumber(b.Lower))
+		default:
+			fmt.Fprintf(bw, " %s: %s <= %s <= %s\n", rowNames[r], formatLPNumber(b.Lower), expr, formatLPNumber(b.Upper))
+		}
+	}
+
+	// Write the bounds.
+	fmt.Fprintln(bw, "Bounds")
+	for _, b := range bounds {
+		if b != "" {
+			fmt.Fprintf(bw, " %s\n", b)
+		}
+	}
+
+	// Write the integer variables.
+	for _, sect := range [...]struct {
+		header string
+		binary bool
+	}{
+		{"Generals", false},
+		{"Binaries", true},
+	} {
+		wroteHeader := false
+		for c := range colNames {
+			if !isInt[c] || binary[c] != sect.binary {
+				continue
+			}
+			if !wroteHeader {
+				fmt.Fprintln(bw, sect.header)
+				wroteHeader = true
+			}
+			fmt.Fprintf(bw, " %s\n", colNames[c])
+		}
+	}
+	fmt.Fprintln(bw, "End")
+	return bw.Flush()
+}
@@ -0,0 +1,170 @@
+// Test reading and writing CPLEX LP files
+
+package clp_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lanl/clp"
+)
+
+// sampleLP is an LP-format version of the problem used in the package
+// documentation, with a few extra features thrown in.
+const sampleLP = `\ Maximize a + b subject to both 0 ≤ 2a + b ≤ 10 and 3 ≤ 2b − a ≤ 8.
+Maximize
+ obj: a + b + 1
+Subject To
+ c1: 0 <= 2 a + b <= 10
+ c2: -a + 2b >= 3
+ c3: - a + 2 b =< 8
+ link: c - a = 0
+Bounds
+ c free
+ -5 <= d <= 5
+Generals
+ d
+Binaries
+ e
+End
+`
+
+// Test if we can read and solve a problem expressed in LP format.
+func TestReadLP(t *testing.T) {
+	simp := clp.NewSimplex()
+	if err := simp.ReadLP(strings.NewReader(sampleLP)); err != nil {
+		t.Fatal(err)
+	}
+	if nr, nc := simp.Dims(); nr != 4 || nc != 5 {
+		t.Fatalf("Expected a 4x5 model but saw %dx%d", nr, nc)
+	}
+	simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+	v := simp.ObjectiveValue()
+	soln := simp.PrimalColumnSolution()
+	if !closeTo(soln[0], 2.4, 0.005) || !closeTo(soln[1], 5.2, 0.005) || !closeTo(soln[2], 2.4, 0.005) {
+		t.Fatalf("Expected [2.4 5.2 2.4 …] but observed %v", soln)
+	}
+	if !closeTo(v, 8.6, 0.005) {
+		t.Fatalf("Expected 8.6 but observed %.10g", v)
+	}
+}
+
+// Test if writing an LP file and reading it back produces the same model.
+func TestLPRoundTrip(t *testing.T) {
+	simp1 := clp.NewSimplex()
+	if err := simp1.ReadLP(strings.NewReader(sampleLP)); err != nil {
+		t.Fatal(err)
+	}
+	var lp1 bytes.Buffer
+	if err := simp1.WriteLP(&lp1); err != nil {
+		t.Fatal(err)
+	}
+	simp2 := clp.NewSimplex()
+	if err := simp2.ReadLP(bytes.NewReader(lp1.Bytes())); err != nil {
+		t.Fatalf("Failed to read back our own LP output (%v):\n%s", err, lp1.String())
+	}
+	var lp2 bytes.Buffer
+	if err := simp2.WriteLP(&lp2); err != nil {
+		t.Fatal(err)
+	}
+	if lp1.String() != lp2.String() {
+		t.Logf("First LP output follows:\n%s", lp1.String())
+		t.Logf("Second LP output follows:\n%s", lp2.String())
+		t.Fatal("Mismatch between the original and round-tripped models")
+	}
+}
+
+// Test if a model converted from LP to MPS and back to LP is unchanged.
+func TestLPMPSRoundTrip(t *testing.T) {
+	const plainLP = `Minimize
+ cost: x + 2 y - 0.5 z
+Subject To
+ r1: 4 <= x + y <= 9
+ r2: 3 x - y + z <= 3
+ r3: y + z = 2
+Bounds
+ x <= 4
+ -1 <= z <= 1
+End
+`
+	simp1 := clp.NewSimplex()
+	if err := simp1.ReadLP(strings.NewReader(plainLP)); err != nil {
+		t.Fatal(err)
+	}
+	mps, err := ioutil.TempFile("", "clp-*.mps")
+	if err != nil {
+		t.Fatalf("Failed to create a temporary MPS file (%v)", err)
+	}
+	mpsName := mps.Name()
+	mps.Close()
+	defer os.Remove(mpsName)
+	if !simp1.WriteMPS(mpsName) {
+		t.Fatalf("Failed to write a simplex model to %s", mpsName)
+	}
+	simp2 := clp.NewSimplex()
+	if err := simp2.ReadMPS(mpsName); err != nil {
+		t.Fatal(err)
+	}
+	var lp1, lp2 bytes.Buffer
+	if err := simp1.WriteLP(&lp1); err != nil {
+		t.Fatal(err)
+	}
+	if err := simp2.WriteLP(&lp2); err != nil {
+		t.Fatal(err)
+	}
+	if lp1.String() != lp2.String() {
+		t.Logf("LP output before MPS conversion follows:\n%s", lp1.String())
+		t.Logf("LP output after MPS conversion follows:\n%s", lp2.String())
+		t.Fatal("Mismatch between the LP and MPS versions of the model")
+	}
+}
+
+// Test if ReadLP returns an error rather than panicking when strict mode
+// rejects the parsed problem.
+func TestReadLPStrict(t *testing.T) {
+	simp := clp.NewSimplex()
+	simp.SetStrict(true)
+	err := simp.ReadLP(strings.NewReader("Minimize\n obj: x\nBounds\n 2 <= x <= 1\nEnd\n"))
+	var ve *clp.ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Expected a *ValidationError but saw %v", err)
+	}
+}
+
+// Test if malformed LP files are reported with the correct line number.
+func TestReadLPErrors(t *testing.T) {
+	for _, tc := range []struct {
+		what string
+		lp   string
+		line int
+	}{
+		{"missing objective sense", "Subject To\n c1: x >= 1\nEnd\n", 1},
+		{"missing relation", "Minimize\n obj: x\nSubject To\n c1: x + y 3\nEnd\n", 4},
+		{"missing right-hand side", "Minimize\n obj: x\nSubject To\n c1: x + y >=\nEnd\n", 5},
+		{"dangling sign", "Minimize\n obj: x\nSubject To\n c1: x + <= 3\nEnd\n", 4},
+		{"inconsistent range", "Minimize\n obj: x\nSubject To\n c1: 1 <= x >= 0\nEnd\n", 4},
+		{"duplicate name", "Minimize\n obj: x\nSubject To\n c1: x >= 1\n c1: x <= 2\nEnd\n", 5},
+		{"malformed bound", "Minimize\n obj: x\nBounds\n <= x\nEnd\n", 4},
+		{"unsupported section", "Minimize\n obj: x\nSOS\n s1: x:1\nEnd\n", 3},
+		{"unexpected character", "Minimize\n obj: x ^ 2\nEnd\n", 2},
+		{"multiple objectives", "Minimize\n obj: x\nMaximize\n obj: x\nEnd\n", 3},
+	} {
+		simp := clp.NewSimplex()
+		err := simp.ReadLP(strings.NewReader(tc.lp))
+		var lpErr *clp.LPError
+		switch {
+		case err == nil:
+			t.Errorf("%s: expected an error but parsing succeeded", tc.what)
+		case !errors.As(err, &lpErr):
+			t.Errorf("%s: expected an *LPError but saw %T (%v)", tc.what, err, err)
+		case !errors.Is(err, clp.ErrInvalidArgument):
+			t.Errorf("%s: expected the error to wrap %v", tc.what, clp.ErrInvalidArgument)
+		case lpErr.Line != tc.line:
+			t.Errorf("%s: expected an error on line %d but saw %q", tc.what, tc.line, err)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+// Algebraic modeling layer
+
+package clp
+
+import "fmt"
+
+// A Model provides an algebraic interface for building linear programs.
+// Rather than constructing a coefficient matrix directly, the caller adds
+// variables and then adds constraints expressed in terms of those variables.
+// Model builds the corresponding PackedMatrix and bounds, and after a solve it
+// maps the solution back to the Variable and Constraint handles.
+type Model struct {
+	vars []*Variable   // All variables in column order
+	cons []*Constraint // All constraints in row order
+}
+
+// NewModel creates a new, empty model.
+func NewModel() *Model {
+	return &Model{
+		vars: make([]*Variable, 0, 16),
+		cons: make([]*Constraint, 0, 16),
+	}
+}
+
+// A Variable represents a single column in a Model.
+type Variable struct {
+	model       *Model  // Model to which the variable belongs
+	index       int     // Column number
+	name        string  // Column name
+	bounds      Bounds  // Lower and upper bound
+	cost        float64 // Objective-function coefficient
+	value       float64 // Primal value after a solve
+	reducedCost float64 // Reduced cost after a solve
+}
+
+// A Term is a variable multiplied by a coefficient.
+type Term struct {
+	Var   *Variable // Variable being multiplied
+	Coeff float64   // Coefficient
+}
+
+// An Expression is a linear combination of variables plus a constant.
+// Expressions are immutable; all operations on them return new Expressions.
+type Expression struct {
+	Terms    []Term  // Variables and their coefficients
+	Constant float64 // Constant term
+}
+
+// A Linear is anything that can be treated as a linear expression, namely
+// either a *Variable or an Expression.
+type Linear interface {
+	Expr() Expression // Return the item as an Expression
+}
+
+// A Constraint represents a single row in a Model.
+type Constraint struct {
+	index    int        // Row number
+	name     string     // Row name
+	expr     Expression // Constrained expression
+	bounds   Bounds     // Lower and upper bound on expr
+	activity float64    // Row activity after a solve
+	dual     float64    // Dual value after a solve
+}
+
+// AddVar adds a variable to a model, given the variable's name, its lower and
+// upper bounds, and its coefficient in the objective function.  Use
+// math.Inf(-1) and math.Inf(1) for unbounded variables.
+func (m *Model) AddVar(name string, lb, ub, cost float64) *Variable {
+	v := &Variable{
+		model:  m,
+		index:  len(m.vars),
+		name:   name,
+		bounds: Bounds{Lower: lb, Upper: ub},
+		cost:   cost,
+	}
+	m.vars = append(m.vars, v)
+	return v
+}
+
+// AddConstraint adds a constraint lb ≤ expr ≤ ub to a model.  Use
+// math.Inf(-1) or math.Inf(1) for a one-sided constraint and lb == ub for an
+// equality constraint.  Any constant term in expr is moved to the bounds.
+// AddConstraint returns an error wrapping ErrInvalidArgument, without
+// adding the constraint, if expr refers to a variable from a different model.
+func (m *Model) AddConstraint(expr Linear, lb, ub float64, name string) (*Constraint, error) {
+	e := expr.Expr()
+	for _, t := range e.Terms {
+		if t.Var.model != m {
+			return nil, fmt.Errorf("%w: Model.AddConstraint given variable %q in constraint %q, which belongs to a different model",
+				ErrInvalidArgument, t.Var.name, name)
+		}
+	}
+	c := &Constraint{
+		index:  len(m.cons),
+		name:   name,
+		expr:   e,
+		bounds: Bounds{Lower: lb, Upper: ub},
+	}
+	m.cons = append(m.cons, c)
+	return c, nil
+}
+
+// Variables returns all of a model's variables in column order.
+func (m *Model) Variables() []*Variable {
+	return append([]*Variable(nil), m.vars...)
+}
+
+// Constraints returns all of a model's constraints in row order.
+func (m *Model) Constraints() []*Constraint {
+	return append([]*Constraint(nil), m.cons...)
+}
+
+// Build converts a model to the arguments expected by Simplex.LoadProblem: a
+// constraint matrix (with row and column names assigned), column bounds,
+// objective-function coefficients, and row bounds.  Repeated variables within
+// a constraint have their coefficients summed.
+func (m *Model) Build() (mat *PackedMatrix, cb []Bounds, obj []float64, rb []Bounds) {
+	// Accumulate each column's nonzeros.
+	cols := make([][]Nonzero, len(m.vars))
+	for r, c := range m.cons {
+		coeffs := make(map[int]float64, len(c.expr.Terms))
+		order := make([]int, 0, len(c.expr.Terms))
+		for _, t := range c.expr.Terms {
+			if _, seen := coeffs[t.Var.index]; !seen {
+				order = append(order, t.Var.index)
+			}
+			coeffs[t.Var.index] += t.Coeff
+		}
+		for _, v := range order {
+			if coeffs[v] != 0.0 {
+				cols[v] = append(cols[v], Nonzero{Index: r, Value: coeffs[v]})
+			}
+		}
+	}
+
+	// Construct the matrix.
+	mat = NewPackedMatrix()
+	for _, col := range cols {
+		mat.AppendColumn(col)
+	}
+	mat.SetDimensions(len(m.cons), len(m.vars))
+
+	// Construct the bounds, names, and objective function.
+	cb = make([]Bounds, len(m.vars))
+	obj = make([]float64, len(m.vars))
+	colNames := make([]string, len(m.vars))
+	for i, v := range m.vars {
+		cb[i] = v.bounds
+		obj[i] = v.cost
+		colNames[i] = v.name
+	}
+	rb = make([]Bounds, len(m.cons))
+	rowNames := make([]string, len(m.cons))
+	for i, c := range m.cons {
+		// Subtracting a constant from an infinite bound leaves it
+		// infinite, as desired.
+		rb[i] = Bounds{
+			Lower: c.bounds.Lower - c.expr.Constant,
+			Upper: c.bounds.Upper - c.expr.Constant,
+		}
+		rowNames[i] = c.name
+	}
+	mat.SetColumnNames(colNames)
+	mat.SetRowNames(rowNames)
+	return
+}
+
+// Load builds a model and loads it into a simplex model.  It returns any
+// error from Simplex.TryLoadProblem, such as a *ValidationError in strict
+// mode.
+func (m *Model) Load(s *Simplex) error {
+	mat, cb, obj, rb := m.Build()
+	return s.TryLoadProblem(mat, cb, obj, rb, nil)
+}
+
+// ReadSolution copies the primal values, reduced costs, row activities, and
+// dual values from a solved simplex model into the model's Variable and
+// Constraint handles.  It returns an error if the simplex model's dimensions
+// do not match the model's.
+func (m *Model) ReadSolution(s *Simplex) error {
+	nr, nc := s.Dims()
+	if nr != len(m.cons) || nc != len(m.vars) {
+		return fmt.Errorf("%w: Model.ReadSolution expected a %dx%d simplex model but saw %dx%d", ErrDimensionMismatch, len(m.cons), len(m.vars), nr, nc)
+	}
+	primCol := s.PrimalColumnSolution()
+	dualCol := s.DualColumnSolution()
+	for i, v := range m.vars {
+		v.value = primCol[i]
+		v.reducedCost = dualCol[i]
+	}
+	primRow := s.PrimalRowSolution()
+	dualRow := s.DualRowSolution()
+	for i, c := range m.cons {
+		c.activity = primRow[i] + c.expr.Constant
+		c.dual = dualRow[i]
+	}
+	return nil
+}
+
+// Name returns a variable's name.
+func (v *Variable) Name() string {
+	return v.name
+}
+
+// Index returns a variable's column number.
+func (v *Variable) Index() int {
+	return v.index
+}
+
+// Bounds returns a variable's lower and upper bounds.
+func (v *Variable) Bounds() Bounds {
+	return v.bounds
+}
+
+// Cost returns a variable's coefficient in the objective function.
+func (v *Variable) Cost() float64 {
+	return v.cost
+}
+
+// Value returns a variable's primal value as of the most recent call to
+// Model.ReadSolution.
+func (v *Variable) Value() float64 {
+	return v.value
+}
+
+// ReducedCost returns a variable's reduced cost as of the most recent call
+// to Model.ReadSolution.
+func (v *Variable) ReducedCost() float64 {
+	return v.reducedCost
+}
+
+// Expr returns a variable as an Expression with a coefficient of 1.
+func (v *Variable) Expr() Expression {
+	return Expression{Terms: []Term{{Var: v, Coeff: 1.0}}}
+}
+
+// Times returns a variable multiplied by a constant.
+func (v *Variable) Times(c float64) Expression {
+	return Expression{Terms: []Term{{Var: v, Coeff: c}}}
+}
+
+// Plus returns the sum of a variable and any number of other linear
+// expressions.
+func (v *Variable) Plus(ls ...Linear) Expression {
+	return v.Expr().Plus(ls...)
+}
+
+// Constant returns an Expression consisting of only a constant term.
+func Constant(c float64) Expression {
+	return Expression{Constant: c}
+}
+
+// Sum returns the sum of any number of linear expressions.
+func Sum(ls ...Linear) Expression {
+	return Expression{}.Plus(ls...)
+}
+
+// Expr returns the expression itself.  It exists to satisfy the Linear
+// interface.
+func (e Expression) Expr() Expression {
+	return e
+}
+
+// Plus returns the sum of an expression and any number of other linear
+// expressions.
+func (e Expression) Plus(ls ...Linear) Expression {
+	sum := Expression{
+		Terms:    append([]Term(nil), e.Terms...),
+		Constant: e.Constant,
+	}
+	for _, l := range ls {
+		le := l.Expr()
+		sum.Terms = append(sum.Terms, le.Terms...)
+		sum.Constant += le.Constant
+	}
+	return sum
+}
+
+// Minus returns the difference of an expression and another linear
+// expression.
+func (e Expression) Minus(l Linear) Expression {
+	return e.Plus(l.Expr().Times(-1.0))
+}
+
+// Times returns an expression multiplied by a constant.
+func (e Expression) Times(c float64) Expression {
+	prod := Expression{
+		Terms:    make([]Term, len(e.Terms)),
+		Constant: e.Constant * c,
+	}
+	for i, t := range e.Terms {
+		prod.Terms[i] = Term{Var: t.Var, Coeff: t.Coeff * c}
+	}
+	return prod
+}
+
+// Name returns a constraint's name.
+func (c *Constraint) Name() string {
+	return c.name
+}
+
+// Index returns a constraint's row number.
+func (c *Constraint) Index() int {
+	return c.index
+}
+
+// Bounds returns a constraint's lower and upper bounds.
+func (c *Constraint) Bounds() Bounds {
+	return c.bounds
+}
+
+// Activity returns the value of a constraint's expression, including any
+// constant term, as of the most recent call to Model.ReadSolution.
+func (c *Constraint) Activity() float64 {
+	return c.activity
+}
+
+// Dual returns a constraint's dual value as of the most recent call to
+// Model.ReadSolution.
+func (c *Constraint) Dual() float64 {
+	return c.dual
+}
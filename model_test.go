@@ -0,0 +1,162 @@
+// Test the algebraic modeling layer
+
+package clp_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/lanl/clp"
+)
+
+// Test if we can solve a transportation problem expressed algebraically.
+func TestModelTransport(t *testing.T) {
+	// Define the problem data.
+	supply := []float64{20, 30}
+	demand := []float64{10, 25, 15}
+	cost := [][]float64{
+		{2, 4, 5},
+		{3, 1, 7},
+	}
+
+	// Create a variable for each supplier-customer pair.
+	m := clp.NewModel()
+	ship := make([][]*clp.Variable, len(supply))
+	for s := range supply {
+		ship[s] = make([]*clp.Variable, len(demand))
+		for d := range demand {
+			name := fmt.Sprintf("ship_%d_%d", s, d)
+			ship[s][d] = m.AddVar(name, 0, math.Inf(1), cost[s][d])
+		}
+	}
+
+	// No supplier can ship more than it has.
+	supCons := make([]*clp.Constraint, len(supply))
+	for s, amt := range supply {
+		var e clp.Expression
+		for d := range demand {
+			e = e.Plus(ship[s][d])
+		}
+		c, err := m.AddConstraint(e, math.Inf(-1), amt, fmt.Sprintf("supply_%d", s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		supCons[s] = c
+	}
+
+	// Every customer must receive exactly what it demands.  To exercise
+	// constants, we express this as shipped − demand = 0.
+	for d, amt := range demand {
+		e := clp.Constant(-amt)
+		for s := range supply {
+			e = e.Plus(ship[s][d])
+		}
+		if _, err := m.AddConstraint(e, 0, 0, fmt.Sprintf("demand_%d", d)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Solve the problem.
+	simp := clp.NewSimplex()
+	if err := m.Load(simp); err != nil {
+		t.Fatal(err)
+	}
+	if nr, nc := simp.Dims(); nr != 5 || nc != 6 {
+		t.Fatalf("Expected a 5x6 model but saw %dx%d", nr, nc)
+	}
+	if name, err := simp.RowName(2); err != nil || name != "demand_0" {
+		t.Fatalf("Expected row 2 to be named demand_0 but saw %q (%v)", name, err)
+	}
+	simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+	if err := m.ReadSolution(simp); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the results.
+	if v := simp.ObjectiveValue(); !closeTo(v, 125, 0.005) {
+		t.Fatalf("Expected 125 but observed %.10g", v)
+	}
+	expected := [][]float64{
+		{5, 0, 15},
+		{5, 25, 0},
+	}
+	for s := range supply {
+		for d := range demand {
+			if v := ship[s][d].Value(); !closeTo(v, expected[s][d], 0.005) {
+				t.Fatalf("Expected %s = %v but observed %v", ship[s][d].Name(), expected[s][d], v)
+			}
+		}
+	}
+	for s, c := range supCons {
+		if a := c.Activity(); !closeTo(a, supply[s], 0.005) {
+			t.Fatalf("Expected %s to have activity %v but observed %v", c.Name(), supply[s], a)
+		}
+	}
+	if rc := ship[0][1].ReducedCost(); rc < -0.005 {
+		t.Fatalf("Expected a nonnegative reduced cost for %s but observed %v", ship[0][1].Name(), rc)
+	}
+}
+
+// Test that ReadSolution rejects a simplex model of the wrong shape.
+func TestModelReadSolutionMismatch(t *testing.T) {
+	m := clp.NewModel()
+	x := m.AddVar("x", 0, 1, 1)
+	if _, err := m.AddConstraint(x, 0, 1, "c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ReadSolution(clp.NewSimplex()); err == nil {
+		t.Fatal("Expected ReadSolution to fail on an empty simplex model")
+	}
+}
+
+// Test that AddConstraint rejects variables from another model and that Load
+// reports problems the simplex model rejects.
+func TestModelErrors(t *testing.T) {
+	m1, m2 := clp.NewModel(), clp.NewModel()
+	x := m1.AddVar("x", 0, 1, 1)
+	if _, err := m2.AddConstraint(x, 0, 1, "c"); !errors.Is(err, clp.ErrInvalidArgument) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrInvalidArgument, err)
+	}
+	if n := len(m2.Constraints()); n != 0 {
+		t.Fatalf("Expected no constraints after a rejected AddConstraint but saw %d", n)
+	}
+
+	// Lower bounds that exceed upper bounds fail validation in strict mode.
+	y := m2.AddVar("y", 2, 1, 1)
+	if _, err := m2.AddConstraint(y, 0, 1, "c"); err != nil {
+		t.Fatal(err)
+	}
+	simp := clp.NewSimplex()
+	simp.SetStrict(true)
+	var ve *clp.ValidationError
+	if err := m2.Load(simp); !errors.As(err, &ve) {
+		t.Fatalf("Expected a *ValidationError but saw %v", err)
+	}
+}
+
+// Maximize a + b subject to both 0 ≤ 2a + b ≤ 10 and 3 ≤ 2b − a ≤ 8.
+func ExampleModel() {
+	// Set up the problem.
+	inf := math.Inf(1)
+	m := clp.NewModel()
+	a := m.AddVar("a", 0, inf, 1)
+	b := m.AddVar("b", 0, inf, 1)
+	m.AddConstraint(a.Times(2).Plus(b), 0, 10, "c1")
+	m.AddConstraint(b.Times(2).Minus(a), 3, 8, "c2")
+	simp := clp.NewSimplex()
+	m.Load(simp)
+	simp.SetOptimizationDirection(clp.Maximize)
+
+	// Solve the optimization problem.
+	simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+	m.ReadSolution(simp)
+
+	// Output the results.
+	fmt.Printf("a = %.1f\nb = %.1f\na + b = %.1f\n", a.Value(), b.Value(), simp.ObjectiveValue())
+	// Output:
+	// a = 2.4
+	// b = 5.2
+	// a + b = 7.6
+}
@@ -13,34 +13,75 @@ import (
 
 // A PackedMatrix is a basic implementation of the Matrix interface.
 type PackedMatrix struct {
-	matrix *C.clp_object    // Pointer to a CoinPackedMatrix
-	allocs []unsafe.Pointer // Row/column data to which the CoinPackedMatrix points
+	matrix   *C.clp_object // Pointer to a CoinPackedMatrix
+	rowNames []string      // Row names, possibly fewer than the number of rows
+	colNames []string      // Column names, possibly fewer than the number of columns
+	err      error         // First CLP exception caught by a method
 }
 
-// NewPackedMatrix allocates a new, empty, packed matrix.
+// NewPackedMatrix allocates a new, empty, packed matrix.  It panics if CLP
+// cannot allocate the matrix.
 func NewPackedMatrix() *PackedMatrix {
-	pm := &PackedMatrix{
-		matrix: C.new_packed_matrix(),
-		allocs: make([]unsafe.Pointer, 0, 64),
+	var cErr C.clp_error
+	matrix := C.new_packed_matrix(&cErr)
+	if err := cError("NewPackedMatrix", &cErr); err != nil {
+		panic(err)
 	}
+	return wrapPackedMatrix(matrix)
+}
+
+// wrapPackedMatrix wraps a PackedMatrix around an existing CoinPackedMatrix,
+// which the PackedMatrix then owns.
+func wrapPackedMatrix(matrix *C.clp_object) *PackedMatrix {
+	pm := &PackedMatrix{matrix: matrix}
 	runtime.SetFinalizer(pm, func(pm *PackedMatrix) {
-		// Free the matrix and all the memory to which it refers.
-		pm.freeMemory()
+		// Free the matrix.  There is no one to whom to report an
+		// error.
+		pm.free()
 	})
 	return pm
 }
 
-// freeMemory immediately frees the memory associated with a packed matrix.
-// The matrix should not be used after this method returns.
-func (pm *PackedMatrix) freeMemory() {
-	if pm.matrix != nil {
-		C.free_packed_matrix(pm.matrix)
-		for _, p := range pm.allocs {
-			cFree(p)
-		}
-		pm.matrix = nil
-		pm.allocs = nil
+// Close immediately frees the C++ object underlying a packed matrix instead
+// of waiting for the garbage collector to do so.  The matrix must not be used
+// after Close returns, but calling Close again is harmless.  A simplex model
+// into which the matrix was loaded has its own copy and is unaffected.  Close
+// returns an error only if CLP throws an exception while freeing the matrix.
+func (pm *PackedMatrix) Close() error {
+	runtime.SetFinalizer(pm, nil)
+	return pm.free()
+}
+
+// free implements Close and the finalizer.
+func (pm *PackedMatrix) free() error {
+	if pm.matrix == nil {
+		return nil
 	}
+	var cErr C.clp_error
+	C.free_packed_matrix(pm.matrix, &cErr)
+	pm.matrix = nil
+	return cError("PackedMatrix.Close", &cErr)
+}
+
+// Err returns the first error that CLP reported, by throwing a C++ exception,
+// during a PackedMatrix method, or nil if there has been no such error.  For
+// example, DeleteColumns reports an error here if given a column number that
+// is out of range.  After an exception, the contents of the matrix are
+// unreliable.
+func (pm *PackedMatrix) Err() error {
+	return pm.err
+}
+
+// recordErr saves err for Err to return unless an earlier error was already
+// saved.  It returns true if err is non-nil.
+func (pm *PackedMatrix) recordErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if pm.err == nil {
+		pm.err = err
+	}
+	return true
 }
 
 // Reserve reserves sufficient space in a packed matrix for appending
@@ -50,117 +91,331 @@ func (pm *PackedMatrix) Reserve(newMaxMajorDim int, newMaxSize int, create bool)
 	if create {
 		b = 1
 	}
-	C.reserve(pm.matrix, C.int(newMaxMajorDim), C.int(newMaxSize), b)
+	var cErr C.clp_error
+	C.reserve(pm.matrix, C.int(newMaxMajorDim), C.int(newMaxSize), b, &cErr)
+	pm.recordErr(cError("PackedMatrix.Reserve", &cErr))
 }
 
 // SetDimensions reserves sufficient space in a packed matrix for appending
 // major-ordered vectors.
 func (pm *PackedMatrix) SetDimensions(numrows, numcols int) {
-	C.set_dimensions(pm.matrix, C.int(numrows), C.int(numcols))
+	var cErr C.clp_error
+	C.set_dimensions(pm.matrix, C.int(numrows), C.int(numcols), &cErr)
+	pm.recordErr(cError("PackedMatrix.SetDimensions", &cErr))
 }
 
 // AppendColumn appends a sparse column to a packed matrix.  The column is
 // specified as a slice of {row number, value} pairs.
 func (pm *PackedMatrix) AppendColumn(col []Nonzero) {
 	// It's not safe to pass Go-allocated memory to C.  Hence, we use C's
-	// malloc to allocate the memory, which we free in the PackedMatrix
-	// finalizer.
+	// malloc to allocate the memory, which we free as soon as CLP has
+	// copied it.
 	nElts := len(col)
-	rows := cMalloc(nElts, C.int(0))
-	pm.allocs = append(pm.allocs, rows)
-	vals := cMalloc(nElts, C.double(0.0))
-	pm.allocs = append(pm.allocs, vals)
+	rows := cMalloc(nElts+1, C.int(0))
+	defer cFree(rows)
+	vals := cMalloc(nElts+1, C.double(0.0))
+	defer cFree(vals)
 
 	// Convert from the given array of two-element structs to two flat
 	// vectors, and replace Go datatypes with C datatypes.
+	rs := cIntSlice(rows, nElts)
+	vs := cDoubleSlice(vals, nElts)
 	for i, c := range col {
-		cSetArrayInt(rows, i, c.Index)
-		cSetArrayDouble(vals, i, c.Value)
+		rs[i] = C.int(c.Index)
+		vs[i] = c.Value
 	}
 
 	// Tell our C wrapper function to append the column.
-	C.pm_append_col(pm.matrix, C.int(nElts), (*C.int)(rows), (*C.double)(vals))
+	var cErr C.clp_error
+	C.pm_append_col(pm.matrix, C.int(nElts), (*C.int)(rows), (*C.double)(vals), &cErr)
+	pm.recordErr(cError("PackedMatrix.AppendColumn", &cErr))
 }
 
 // AppendRow appends a sparse row to a packed matrix.  The row is
 // specified as a slice of {column number, value} pairs.
 func (pm *PackedMatrix) AppendRow(row []Nonzero) {
 	// It's not safe to pass Go-allocated memory to C.  Hence, we use C's
-	// malloc to allocate the memory, which we free in the PackedMatrix
-	// finalizer.
+	// malloc to allocate the memory, which we free as soon as CLP has
+	// copied it.
 	nElts := len(row)
-	cols := cMalloc(nElts, C.int(0))
-	pm.allocs = append(pm.allocs, cols)
-	vals := cMalloc(nElts, C.double(0.0))
-	pm.allocs = append(pm.allocs, vals)
+	cols := cMalloc(nElts+1, C.int(0))
+	defer cFree(cols)
+	vals := cMalloc(nElts+1, C.double(0.0))
+	defer cFree(vals)
 
 	// Convert from the given array of two-element structs to two flat
 	// vectors, and replace Go datatypes with C datatypes.
+	cs := cIntSlice(cols, nElts)
+	vs := cDoubleSlice(vals, nElts)
 	for i, r := range row {
-		cSetArrayInt(cols, i, r.Index)
-		cSetArrayDouble(vals, i, r.Value)
+		cs[i] = C.int(r.Index)
+		vs[i] = r.Value
 	}
 
 	// Tell our C wrapper function to append the row.
-	C.pm_append_row(pm.matrix, C.int(nElts), (*C.int)(cols), (*C.double)(vals))
+	var cErr C.clp_error
+	C.pm_append_row(pm.matrix, C.int(nElts), (*C.int)(cols), (*C.double)(vals), &cErr)
+	pm.recordErr(cError("PackedMatrix.AppendRow", &cErr))
 }
 
 // DeleteColumns removes a list of columns from a packed matrix.
 func (pm *PackedMatrix) DeleteColumns(cols []int) {
 	nc := len(cols)
-	cs := cMalloc(nc, C.int(0))
-	for i, c := range cols {
-		cSetArrayInt(cs, i, c)
-	}
-	C.pm_delete_cols(pm.matrix, C.int(nc), (*C.int)(cs))
+	cs := cMalloc(nc+1, C.int(0))
+	defer cFree(cs)
+	copyIntsGoC(cIntSlice(cs, nc), cols)
+	var cErr C.clp_error
+	C.pm_delete_cols(pm.matrix, C.int(nc), (*C.int)(cs), &cErr)
+	pm.recordErr(cError("PackedMatrix.DeleteColumns", &cErr))
+	pm.colNames = deleteNames(pm.colNames, cols)
 }
 
 // DeleteRows removes a list of rows from a packed matrix.
 func (pm *PackedMatrix) DeleteRows(rows []int) {
 	nr := len(rows)
-	rs := cMalloc(nr, C.int(0))
-	for i, r := range rows {
-		cSetArrayInt(rs, i, r)
+	rs := cMalloc(nr+1, C.int(0))
+	defer cFree(rs)
+	copyIntsGoC(cIntSlice(rs, nr), rows)
+	var cErr C.clp_error
+	C.pm_delete_rows(pm.matrix, C.int(nr), (*C.int)(rs), &cErr)
+	pm.recordErr(cError("PackedMatrix.DeleteRows", &cErr))
+	pm.rowNames = deleteNames(pm.rowNames, rows)
+}
+
+// deleteNames removes the names at the given indices from a list of names.
+func deleteNames(names []string, del []int) []string {
+	if len(names) == 0 {
+		return names
+	}
+	drop := make(map[int]bool, len(del))
+	for _, d := range del {
+		drop[d] = true
+	}
+	kept := make([]string, 0, len(names))
+	for i, n := range names {
+		if !drop[i] {
+			kept = append(kept, n)
+		}
 	}
-	C.pm_delete_rows(pm.matrix, C.int(nr), (*C.int)(rs))
+	return kept
+}
+
+// setName assigns names[idx] = name, growing names if necessary.
+func setName(names []string, idx int, name string) []string {
+	for len(names) <= idx {
+		names = append(names, "")
+	}
+	names[idx] = name
+	return names
+}
+
+// SetRowName assigns a name to a row.  CoinPackedMatrix has no notion of
+// names, so these are maintained on the Go side and passed to the model by
+// Simplex.LoadProblem.
+func (pm *PackedMatrix) SetRowName(row int, name string) {
+	pm.rowNames = setName(pm.rowNames, row, name)
+}
+
+// SetColumnName assigns a name to a column.  CoinPackedMatrix has no notion
+// of names, so these are maintained on the Go side and passed to the model by
+// Simplex.LoadProblem.
+func (pm *PackedMatrix) SetColumnName(col int, name string) {
+	pm.colNames = setName(pm.colNames, col, name)
+}
+
+// RowName returns the name of a row or the empty string if the row was not
+// assigned a name.
+func (pm *PackedMatrix) RowName(row int) string {
+	if row < len(pm.rowNames) {
+		return pm.rowNames[row]
+	}
+	return ""
+}
+
+// ColumnName returns the name of a column or the empty string if the column
+// was not assigned a name.
+func (pm *PackedMatrix) ColumnName(col int) string {
+	if col < len(pm.colNames) {
+		return pm.colNames[col]
+	}
+	return ""
+}
+
+// SetRowNames assigns names to the first len(names) rows.
+func (pm *PackedMatrix) SetRowNames(names []string) {
+	pm.rowNames = append([]string(nil), names...)
+}
+
+// SetColumnNames assigns names to the first len(names) columns.
+func (pm *PackedMatrix) SetColumnNames(names []string) {
+	pm.colNames = append([]string(nil), names...)
+}
+
+// RowNames returns the names of all rows, with the empty string standing in
+// for unassigned names.
+func (pm *PackedMatrix) RowNames() []string {
+	nr, _ := pm.Dims()
+	names := make([]string, nr)
+	copy(names, pm.rowNames)
+	return names
+}
+
+// ColumnNames returns the names of all columns, with the empty string
+// standing in for unassigned names.
+func (pm *PackedMatrix) ColumnNames() []string {
+	_, nc := pm.Dims()
+	names := make([]string, nc)
+	copy(names, pm.colNames)
+	return names
+}
+
+// hasNames says whether any row or column of a packed matrix was assigned a
+// name.
+func (pm *PackedMatrix) hasNames() bool {
+	return len(pm.rowNames) > 0 || len(pm.colNames) > 0
 }
 
 // Dims returns a packed matrix's dimensions (rows and columns).
 func (pm *PackedMatrix) Dims() (rows, cols int) {
 	var r, c C.int
-	C.pm_get_dims(pm.matrix, &r, &c)
+	var cErr C.clp_error
+	C.pm_get_dims(pm.matrix, &r, &c, &cErr)
+	pm.recordErr(cError("PackedMatrix.Dims", &cErr))
 	rows = int(r)
 	cols = int(c)
 	return
 }
 
-// SparseData returns a packed matrix's data in a sparse representation.  It
-// corresponds to the getVectorStarts(), getVectorLengths(), getIndices(), and
-// getElements() methods in the CLP library's CoinPackedMatrix class.
+// IsColumnOrdered says whether a packed matrix stores its elements column by
+// column, which is the default, rather than row by row.
+func (pm *PackedMatrix) IsColumnOrdered() bool {
+	var cErr C.clp_error
+	co := C.pm_is_col_ordered(pm.matrix, &cErr)
+	pm.recordErr(cError("PackedMatrix.IsColumnOrdered", &cErr))
+	return co != 0
+}
+
+// ReverseOrdering switches a packed matrix from column ordering to row
+// ordering or vice versa without changing its contents.  Appending rows to a
+// row-ordered matrix is as efficient as appending columns to a
+// column-ordered matrix, so a program that generates one constraint at a
+// time should call ReverseOrdering on a new, empty matrix before calling
+// AppendRow.  Simplex.LoadProblem accepts matrices with either ordering.
+func (pm *PackedMatrix) ReverseOrdering() {
+	var cErr C.clp_error
+	C.pm_reverse_ordering(pm.matrix, &cErr)
+	pm.recordErr(cError("PackedMatrix.ReverseOrdering", &cErr))
+}
+
+// Transpose transposes a packed matrix in place, turning rows into columns
+// and columns into rows.  Row and column names are exchanged as well.
+// Transpose takes constant time because it merely reinterprets the stored
+// vectors, so a column-ordered matrix becomes row-ordered and vice versa.
+func (pm *PackedMatrix) Transpose() {
+	var cErr C.clp_error
+	C.pm_transpose(pm.matrix, &cErr)
+	pm.recordErr(cError("PackedMatrix.Transpose", &cErr))
+	pm.rowNames, pm.colNames = pm.colNames, pm.rowNames
+}
+
+// SparseData returns a packed matrix's data in a sparse, column-ordered
+// representation, as required by the Matrix interface.  For a column-ordered
+// matrix, it corresponds to the getVectorStarts(), getVectorLengths(),
+// getIndices(), and getElements() methods in the CLP library's
+// CoinPackedMatrix class.  A row-ordered matrix's data are converted to
+// column order; use OrderedSparseData to retrieve them unconverted.
 func (pm *PackedMatrix) SparseData() (starts, lengths, indices []int, elements []float64) {
+	colOrdered, starts, lengths, indices, elements := pm.OrderedSparseData()
+	if colOrdered || starts == nil {
+		return
+	}
+	_, nc := pm.Dims()
+	return reverseSparseData(nc, starts, lengths, indices, elements)
+}
+
+// OrderedSparseData is like SparseData but returns a packed matrix's data in
+// the matrix's own ordering, which it reports in colOrdered.  If colOrdered
+// is true, the vectors described by starts and lengths are columns, and
+// indices holds row numbers, exactly as in SparseData.  Otherwise, the
+// vectors are rows, and indices holds column numbers.
+func (pm *PackedMatrix) OrderedSparseData() (colOrdered bool, starts, lengths, indices []int, elements []float64) {
 	// Retrieve pointers into the matrix's internal state.
+	colOrdered = pm.IsColumnOrdered()
 	var cstarts *C.int
 	var clens *C.int
 	var cidxs *C.int
 	var celts *C.double
-	C.pm_get_sparse_data(pm.matrix, &cstarts, &clens, &cidxs, &celts)
+	var cErr C.clp_error
+	C.pm_get_sparse_data(pm.matrix, &cstarts, &clens, &cidxs, &celts, &cErr)
+	if pm.recordErr(cError("PackedMatrix.OrderedSparseData", &cErr)) {
+		return
+	}
 
-	// Convert from C arrays to Go slices.  We assume column ordering
-	// because we don't yet give the user the ability to change the
-	// ordering from the default column-ordered.
-	_, nc := pm.Dims()
-	starts = make([]int, nc)
-	lengths = make([]int, nc)
+	// Convert from C arrays to Go slices.  There is one vector per column
+	// in a column-ordered matrix and one per row in a row-ordered matrix.
+	// CoinPackedMatrix may leave gaps between vectors, which we close up,
+	// so starts can differ from CLP's.
+	nr, nc := pm.Dims()
+	major := nc
+	if !colOrdered {
+		major = nr
+	}
+	cStarts := cIntSlice(unsafe.Pointer(cstarts), major)
+	cLengths := cIntSlice(unsafe.Pointer(clens), major)
+	starts = make([]int, major)
+	lengths = make([]int, major)
+	nnz, size := 0, 0
 	for i := range starts {
-		starts[i] = cGetArrayInt(unsafe.Pointer(cstarts), i)
-		lengths[i] = cGetArrayInt(unsafe.Pointer(clens), i)
+		starts[i] = nnz
+		lengths[i] = int(cLengths[i])
+		nnz += lengths[i]
+		if end := int(cStarts[i]) + lengths[i]; end > size {
+			size = end
+		}
 	}
-	indices = make([]int, 0, nc)
-	elements = make([]float64, 0, nc)
-	for i := 0; i < nc; i++ {
-		for j := starts[i]; j < starts[i]+lengths[i]; j++ {
-			indices = append(indices, cGetArrayInt(unsafe.Pointer(cidxs), j))
-			elements = append(elements, cGetArrayDouble(unsafe.Pointer(celts), j))
+
+	// Copy each vector's indices and elements in bulk.
+	cIndices := cIntSlice(unsafe.Pointer(cidxs), size)
+	cElements := cDoubleSlice(unsafe.Pointer(celts), size)
+	indices = make([]int, nnz)
+	elements = make([]float64, nnz)
+	for i, st := range starts {
+		cst := int(cStarts[i])
+		n := lengths[i]
+		copyIntsCGo(indices[st:st+n], cIndices[cst:cst+n])
+		copy(elements[st:st+n], cElements[cst:cst+n])
+	}
+	runtime.KeepAlive(pm)
+	return
+}
+
+// reverseSparseData converts gap-free sparse data from row ordering to
+// column ordering or vice versa.  nMinor is the number of vectors in the
+// result.
+func reverseSparseData(nMinor int, starts, lengths, indices []int, elements []float64) (rStarts, rLengths, rIndices []int, rElements []float64) {
+	// Count the elements in each resulting vector.
+	rLengths = make([]int, nMinor)
+	for _, idx := range indices {
+		rLengths[idx]++
+	}
+	rStarts = make([]int, nMinor)
+	next := make([]int, nMinor)
+	nnz := 0
+	for i, n := range rLengths {
+		rStarts[i] = nnz
+		next[i] = nnz
+		nnz += n
+	}
+
+	// Distribute the elements to their new positions.
+	rIndices = make([]int, nnz)
+	rElements = make([]float64, nnz)
+	for maj, st := range starts {
+		for k := st; k < st+lengths[maj]; k++ {
+			idx := indices[k]
+			rIndices[next[idx]] = maj
+			rElements[next[idx]] = elements[k]
+			next[idx]++
 		}
 	}
 	return
@@ -178,13 +433,17 @@ func (pm *PackedMatrix) DenseData() [][]float64 {
 		mat[r] = make([]float64, nc)
 	}
 
-	// Populate the dense matrix from the sparse representation.
-	starts, lengths, indices, elements := pm.SparseData()
-	for c, st := range starts {
-		iend := st + lengths[c]
+	// Populate the dense matrix from the sparse representation, which
+	// we use in its native ordering to avoid a conversion.
+	colOrdered, starts, lengths, indices, elements := pm.OrderedSparseData()
+	for maj, st := range starts {
+		iend := st + lengths[maj]
 		for i := st; i < iend; i++ {
-			r := indices[i]
-			mat[r][c] = elements[i]
+			if colOrdered {
+				mat[indices[i]][maj] = elements[i]
+			} else {
+				mat[maj][indices[i]] = elements[i]
+			}
 		}
 	}
 	return mat
@@ -196,7 +455,7 @@ func (pm *PackedMatrix) DumpMatrix(w io.Writer) error {
 	// Reproduce CoinPackedMatrix::dumpMatrix() from CoinPackedMatrix.cpp.
 	// We don't call the original C++ method because it writes to a file,
 	// while we'd prefer to use an io.Writer.
-	starts, lengths, indices, elements := pm.SparseData()
+	colOrdered, starts, lengths, indices, elements := pm.OrderedSparseData()
 	var err error
 	printf := func(format string, a ...interface{}) {
 		// Borrow the error-checking trick from "Errors are values"
@@ -207,8 +466,12 @@ func (pm *PackedMatrix) DumpMatrix(w io.Writer) error {
 		_, err = fmt.Fprintf(w, format, a...)
 	}
 	printf("Dumping matrix...\n\n")
-	printf("colordered: %d\n", 1) // Only column ordered is currently supported.
-	minor, major := pm.Dims()
+	nr, nc := pm.Dims()
+	major, minor, co := nc, nr, 1
+	if !colOrdered {
+		major, minor, co = nr, nc, 0
+	}
+	printf("colordered: %d\n", co)
 	printf("major: %d   minor: %d\n", major, minor)
 	for i := 0; i < major; i++ {
 		printf("vec %d has length %d with entries:\n", i, lengths[i])
@@ -6,6 +6,7 @@ package clp_test
 import (
 	"bytes"
 	"github.com/lanl/clp"
+	"reflect"
 	"testing"
 )
 
@@ -104,3 +105,108 @@ Finished dumping matrix
 		t.Fatalf("Mismatch between expected and actual matrix contents")
 	}
 }
+
+// Test if row and column names track row and column deletions.
+func TestMatrixNames(t *testing.T) {
+	m := clp.NewPackedMatrix()
+	addColumns(m, 5, 5)
+	m.SetColumnNames([]string{"c0", "c1", "c2", "c3", "c4"})
+	m.SetRowName(3, "r3")
+	m.DeleteColumns([]int{1, 3})
+	if names := m.ColumnNames(); len(names) != 3 || names[0] != "c0" || names[1] != "c2" || names[2] != "c4" {
+		t.Fatalf("Expected column names [c0 c2 c4] but saw %q", names)
+	}
+	m.DeleteRows([]int{0})
+	if name := m.RowName(2); name != "r3" {
+		t.Fatalf("Expected row 2 to be named r3 but saw %q", name)
+	}
+	if name := m.RowName(0); name != "" {
+		t.Fatalf("Expected row 0 to be unnamed but saw %q", name)
+	}
+}
+
+// Test if row-ordered matrices behave like column-ordered matrices.
+func TestOrdering(t *testing.T) {
+	// Build the same 2×3 matrix with each ordering.
+	cm := clp.NewPackedMatrix()
+	cm.AppendColumn([]clp.Nonzero{{Index: 0, Value: 1}, {Index: 1, Value: 4}})
+	cm.AppendColumn([]clp.Nonzero{{Index: 1, Value: 5}})
+	cm.AppendColumn([]clp.Nonzero{{Index: 0, Value: 3}})
+	rm := clp.NewPackedMatrix()
+	rm.ReverseOrdering()
+	rm.AppendRow([]clp.Nonzero{{Index: 0, Value: 1}, {Index: 2, Value: 3}})
+	rm.AppendRow([]clp.Nonzero{{Index: 0, Value: 4}, {Index: 1, Value: 5}})
+	if !cm.IsColumnOrdered() || rm.IsColumnOrdered() {
+		t.Fatalf("Expected orderings true and false but saw %v and %v", cm.IsColumnOrdered(), rm.IsColumnOrdered())
+	}
+
+	// Both matrices should report the same column-ordered data.
+	if cd, rd := cm.DenseData(), rm.DenseData(); !reflect.DeepEqual(cd, rd) {
+		t.Fatalf("Expected %v but saw %v", cd, rd)
+	}
+	cs, cl, ci, ce := cm.SparseData()
+	rs, rl, ri, re := rm.SparseData()
+	if !reflect.DeepEqual(cs, rs) || !reflect.DeepEqual(cl, rl) ||
+		!reflect.DeepEqual(ci, ri) || !reflect.DeepEqual(ce, re) {
+		t.Fatalf("Expected %v %v %v %v but saw %v %v %v %v", cs, cl, ci, ce, rs, rl, ri, re)
+	}
+
+	// The row-ordered matrix's native data should be organized by row.
+	co, starts, lengths, indices, elements := rm.OrderedSparseData()
+	if co || !reflect.DeepEqual(starts, []int{0, 2}) || !reflect.DeepEqual(lengths, []int{2, 2}) ||
+		!reflect.DeepEqual(indices, []int{0, 2, 0, 1}) || !reflect.DeepEqual(elements, []float64{1, 3, 4, 5}) {
+		t.Fatalf("Saw unexpected row-ordered data %v %v %v %v %v", co, starts, lengths, indices, elements)
+	}
+
+	// Reversing the ordering should preserve the contents.
+	rm.ReverseOrdering()
+	if !rm.IsColumnOrdered() {
+		t.Fatal("Expected ReverseOrdering to produce a column-ordered matrix")
+	}
+	if cd, rd := cm.DenseData(), rm.DenseData(); !reflect.DeepEqual(cd, rd) {
+		t.Fatalf("Expected %v but saw %v", cd, rd)
+	}
+
+	// Transposing should exchange rows and columns, including names.
+	cm.SetRowName(0, "r0")
+	cm.SetColumnName(2, "c2")
+	cm.Transpose()
+	if nr, nc := cm.Dims(); nr != 3 || nc != 2 {
+		t.Fatalf("Expected 3×2 but saw %d×%d", nr, nc)
+	}
+	if cm.IsColumnOrdered() {
+		t.Fatal("Expected Transpose to produce a row-ordered matrix")
+	}
+	expected := [][]float64{{1, 4}, {0, 5}, {3, 0}}
+	if actual := cm.DenseData(); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v but saw %v", expected, actual)
+	}
+	if cm.RowName(2) != "c2" || cm.ColumnName(0) != "r0" {
+		t.Fatalf("Expected names c2 and r0 but saw %q and %q", cm.RowName(2), cm.ColumnName(0))
+	}
+	var buf bytes.Buffer
+	cm.DumpMatrix(&buf)
+	if !bytes.Contains(buf.Bytes(), []byte("colordered: 0\nmajor: 3   minor: 2\n")) {
+		t.Fatalf("Saw unexpected dump of a row-ordered matrix:\n%s", buf.String())
+	}
+}
+
+// Benchmark appending columns to a packed matrix.
+func BenchmarkAppendColumn(b *testing.B) {
+	col := []clp.Nonzero{{Index: 0, Value: 1}, {Index: 5, Value: 2}, {Index: 9, Value: 3}}
+	m := clp.NewPackedMatrix()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.AppendColumn(col)
+	}
+}
+
+// Benchmark extracting the data from a large packed matrix.
+func BenchmarkSparseData(b *testing.B) {
+	m := clp.NewPackedMatrix()
+	addColumns(m, 1000, 100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _, _ = m.SparseData()
+	}
+}
@@ -0,0 +1,240 @@
+// Solver parameters
+
+package clp
+
+// #include "clp-interface.h"
+import "C"
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Params holds the parameters that control a simplex solve.  The usual way to
+// modify them is to retrieve the current values with Simplex.Params, change
+// the fields of interest, and install the result with Simplex.SetParams.
+// Model data such as the objective offset are not included.
+type Params struct {
+	MaxIterations          int     // Maximum number of iterations in a solve
+	MaxIterationsHotStart  int     // Maximum number of iterations in a hot start
+	NameDiscipline         int     // 0 for no row or column names, 1 for names only where given, 2 for a name for every row and column
+	Perturbation           int     // 50 to perturb the problem, 100 to perturb only if the solve stalls, 102 never to perturb; other values in [−5000, 102] are experimental
+	FactorizationFrequency int     // Maximum number of pivots between refactorizations
+	MaxSeconds             float64 // Maximum CPU time for a solve in seconds, or a negative number for no limit
+	MaxWallSeconds         float64 // Maximum wall-clock time for a solve in seconds, or a negative number for no limit
+	PrimalTolerance        float64 // Amount by which a variable may violate its bounds and still be deemed primal feasible
+	DualTolerance          float64 // Amount by which a reduced cost may have the wrong sign and still be deemed dual feasible
+	PrimalObjectiveLimit   float64 // Stop the primal method once the objective is better than this
+	DualObjectiveLimit     float64 // Stop the dual method once the objective is worse than this
+	PresolveTolerance      float64 // Tolerance used to detect infeasibility during presolve
+	DualBound              float64 // Artificial bound placed on otherwise unbounded variables by the dual method
+	InfeasibilityCost      float64 // Weight given to infeasibilities by the primal method's composite objective
+}
+
+// maxTolerance is the largest tolerance that CLP accepts.
+const maxTolerance = 1.0e10
+
+// check returns an error wrapping ErrInvalidArgument if any parameter lies
+// outside the range that CLP accepts.  what names the caller.
+func (p Params) check(what string) error {
+	bad := func(name string, v interface{}, want string) error {
+		return fmt.Errorf("%w: %s given %s = %v but it must be %s", ErrInvalidArgument, what, name, v, want)
+	}
+	switch {
+	case p.MaxIterations < 0:
+		return bad("MaxIterations", p.MaxIterations, "nonnegative")
+	case p.MaxIterationsHotStart < 0:
+		return bad("MaxIterationsHotStart", p.MaxIterationsHotStart, "nonnegative")
+	case p.NameDiscipline < 0 || p.NameDiscipline > 2:
+		return bad("NameDiscipline", p.NameDiscipline, "0, 1, or 2")
+	case p.Perturbation < -5000 || p.Perturbation > 102:
+		return bad("Perturbation", p.Perturbation, "in [-5000, 102]")
+	case p.FactorizationFrequency < 1:
+		return bad("FactorizationFrequency", p.FactorizationFrequency, "positive")
+	case math.IsNaN(p.MaxSeconds):
+		return bad("MaxSeconds", p.MaxSeconds, "a number")
+	case math.IsNaN(p.MaxWallSeconds):
+		return bad("MaxWallSeconds", p.MaxWallSeconds, "a number")
+	case !(p.PrimalTolerance > 0 && p.PrimalTolerance <= maxTolerance):
+		return bad("PrimalTolerance", p.PrimalTolerance, fmt.Sprintf("in (0, %g]", maxTolerance))
+	case !(p.DualTolerance > 0 && p.DualTolerance <= maxTolerance):
+		return bad("DualTolerance", p.DualTolerance, fmt.Sprintf("in (0, %g]", maxTolerance))
+	case math.IsNaN(p.PrimalObjectiveLimit):
+		return bad("PrimalObjectiveLimit", p.PrimalObjectiveLimit, "a number")
+	case math.IsNaN(p.DualObjectiveLimit):
+		return bad("DualObjectiveLimit", p.DualObjectiveLimit, "a number")
+	case !(p.PresolveTolerance > 0 && p.PresolveTolerance <= maxTolerance):
+		return bad("PresolveTolerance", p.PresolveTolerance, fmt.Sprintf("in (0, %g]", maxTolerance))
+	case !(p.DualBound > 0) || math.IsInf(p.DualBound, 1):
+		return bad("DualBound", p.DualBound, "positive and finite")
+	case !(p.InfeasibilityCost > 0) || math.IsInf(p.InfeasibilityCost, 1):
+		return bad("InfeasibilityCost", p.InfeasibilityCost, "positive and finite")
+	}
+	return nil
+}
+
+// Params returns a model's current solver parameters.  If CLP throws an
+// exception, Params returns the zero value, and Err reports the exception.
+func (s *Simplex) Params() Params {
+	var cp C.clp_params
+	var cErr C.clp_error
+	C.simplex_get_params(s.model, &cp, &cErr)
+	if s.recordErr(cError("Simplex.Params", &cErr)) {
+		return Params{}
+	}
+	return Params{
+		MaxIterations:          int(cp.max_iterations),
+		MaxIterationsHotStart:  int(cp.max_iterations_hot_start),
+		NameDiscipline:         int(cp.name_discipline),
+		Perturbation:           int(cp.perturbation),
+		FactorizationFrequency: int(cp.factorization_frequency),
+		MaxSeconds:             float64(cp.max_seconds),
+		MaxWallSeconds:         float64(cp.max_wall_seconds),
+		PrimalTolerance:        float64(cp.primal_tolerance),
+		DualTolerance:          float64(cp.dual_tolerance),
+		PrimalObjectiveLimit:   float64(cp.primal_objective_limit),
+		DualObjectiveLimit:     float64(cp.dual_objective_limit),
+		PresolveTolerance:      float64(cp.presolve_tolerance),
+		DualBound:              float64(cp.dual_bound),
+		InfeasibilityCost:      float64(cp.infeasibility_cost),
+	}
+}
+
+// SetParams replaces all of a model's solver parameters.  It returns an error
+// wrapping ErrInvalidArgument, without changing any parameter, if any of them
+// lies outside its valid range, and an error wrapping ErrException if CLP
+// throws an exception.
+func (s *Simplex) SetParams(p Params) error {
+	if err := p.check("Simplex.SetParams"); err != nil {
+		return err
+	}
+	cp := C.clp_params{
+		max_iterations:           C.int(p.MaxIterations),
+		max_iterations_hot_start: C.int(p.MaxIterationsHotStart),
+		name_discipline:          C.int(p.NameDiscipline),
+		perturbation:             C.int(p.Perturbation),
+		factorization_frequency:  C.int(p.FactorizationFrequency),
+		max_seconds:              C.double(p.MaxSeconds),
+		max_wall_seconds:         C.double(p.MaxWallSeconds),
+		primal_tolerance:         C.double(p.PrimalTolerance),
+		dual_tolerance:           C.double(p.DualTolerance),
+		primal_objective_limit:   C.double(p.PrimalObjectiveLimit),
+		dual_objective_limit:     C.double(p.DualObjectiveLimit),
+		presolve_tolerance:       C.double(p.PresolveTolerance),
+		dual_bound:               C.double(p.DualBound),
+		infeasibility_cost:       C.double(p.InfeasibilityCost),
+	}
+	var cErr C.clp_error
+	C.simplex_set_params(s.model, &cp, &cErr)
+	return cError("Simplex.SetParams", &cErr)
+}
+
+// A paramKey associates the names by which a parameter can appear in a
+// parameter file with the field that holds it.
+type paramKey struct {
+	names []string                    // Accepted names, in lowercase
+	field func(p *Params) interface{} // Pointer to an int or float64 field
+}
+
+// paramKeys lists every parameter that can appear in a parameter file.  Each
+// can be named by its Params field or by the corresponding option to CLP's
+// standalone solver.
+var paramKeys = []paramKey{
+	{[]string{"maxiterations"}, func(p *Params) interface{} { return &p.MaxIterations }},
+	{[]string{"maxiterationshotstart", "hotstartmaxits"}, func(p *Params) interface{} { return &p.MaxIterationsHotStart }},
+	{[]string{"namediscipline"}, func(p *Params) interface{} { return &p.NameDiscipline }},
+	{[]string{"perturbation", "pertvalue"}, func(p *Params) interface{} { return &p.Perturbation }},
+	{[]string{"factorizationfrequency", "maxfactor"}, func(p *Params) interface{} { return &p.FactorizationFrequency }},
+	{[]string{"maxseconds", "seconds"}, func(p *Params) interface{} { return &p.MaxSeconds }},
+	{[]string{"maxwallseconds"}, func(p *Params) interface{} { return &p.MaxWallSeconds }},
+	{[]string{"primaltolerance"}, func(p *Params) interface{} { return &p.PrimalTolerance }},
+	{[]string{"dualtolerance"}, func(p *Params) interface{} { return &p.DualTolerance }},
+	{[]string{"primalobjectivelimit"}, func(p *Params) interface{} { return &p.PrimalObjectiveLimit }},
+	{[]string{"dualobjectivelimit"}, func(p *Params) interface{} { return &p.DualObjectiveLimit }},
+	{[]string{"presolvetolerance", "pretolerance"}, func(p *Params) interface{} { return &p.PresolveTolerance }},
+	{[]string{"dualbound"}, func(p *Params) interface{} { return &p.DualBound }},
+	{[]string{"infeasibilitycost", "primalweight"}, func(p *Params) interface{} { return &p.InfeasibilityCost }},
+}
+
+// ParamsFromFile reads solver parameters from the named file and returns
+// them, starting from CLP's defaults for any parameters the file does not
+// mention.  Each line of the file has the form "key = value", where key is
+// either the name of a Params field or the name of the corresponding option
+// to CLP's standalone solver (such as "maxFactor" for FactorizationFrequency
+// or "primalWeight" for InfeasibilityCost), compared case-insensitively.
+// Blank lines and lines beginning with "#" are ignored.  ParamsFromFile
+// returns an error wrapping ErrIO if the file cannot be read and an error
+// wrapping ErrInvalidArgument if it is malformed or specifies an invalid
+// value.
+func ParamsFromFile(filename string) (Params, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return Params{}, fmt.Errorf("%w: %v", ErrIO, err)
+	}
+	defer f.Close()
+	s := NewSimplex()
+	defer s.Close()
+	p := s.Params()
+	if err := s.Err(); err != nil {
+		return Params{}, err
+	}
+	if err := readParams(f, filename, &p); err != nil {
+		return Params{}, err
+	}
+	return p, p.check("ParamsFromFile")
+}
+
+// readParams implements ParamsFromFile, overwriting fields of p with the
+// values read from r.
+func readParams(r io.Reader, filename string, p *Params) error {
+	keys := make(map[string]paramKey)
+	for _, k := range paramKeys {
+		for _, n := range k.names {
+			keys[n] = k
+		}
+	}
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		errorf := func(format string, a ...interface{}) error {
+			return fmt.Errorf("%w: %s line %d: %s", ErrInvalidArgument, filename, lineNum, fmt.Sprintf(format, a...))
+		}
+		eq := strings.Index(line, "=")
+		if eq < 0 {
+			return errorf("expected key = value but saw %q", line)
+		}
+		name := strings.TrimSpace(line[:eq])
+		val := strings.TrimSpace(line[eq+1:])
+		k, ok := keys[strings.ToLower(name)]
+		if !ok {
+			return errorf("unknown parameter %q", name)
+		}
+		switch f := k.field(p).(type) {
+		case *int:
+			v, err := strconv.Atoi(val)
+			if err != nil {
+				return errorf("expected an integer value for %s but saw %q", name, val)
+			}
+			*f = v
+		case *float64:
+			v, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return errorf("expected a numeric value for %s but saw %q", name, val)
+			}
+			*f = v
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrIO, err)
+	}
+	return nil
+}
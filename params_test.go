@@ -0,0 +1,107 @@
+// Test solver parameters
+
+package clp_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/lanl/clp"
+)
+
+// Test if we can get and set solver parameters.
+func TestParams(t *testing.T) {
+	// The parameters should agree with the individual getters.
+	simp := clp.NewSimplex()
+	p := simp.Params()
+	if err := simp.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if p.MaxIterations != simp.MaxIterations() || p.PrimalTolerance != simp.PrimalTolerance() {
+		t.Fatalf("Expected %d and %v but saw %d and %v",
+			simp.MaxIterations(), simp.PrimalTolerance(), p.MaxIterations, p.PrimalTolerance)
+	}
+
+	// Modified parameters should be read back unchanged.
+	p.MaxIterations = 1234
+	p.DualTolerance = 1e-6
+	p.DualBound = 1e8
+	p.InfeasibilityCost = 1e9
+	p.Perturbation = 50
+	p.FactorizationFrequency = 150
+	p.MaxWallSeconds = 60
+	if err := simp.SetParams(p); err != nil {
+		t.Fatal(err)
+	}
+	if act := simp.Params(); act != p {
+		t.Fatalf("Expected %+v but saw %+v", p, act)
+	}
+	if simp.MaxIterations() != 1234 {
+		t.Fatalf("Expected 1234 iterations but saw %d", simp.MaxIterations())
+	}
+
+	// Invalid parameters should be rejected without changing anything.
+	bad := p
+	bad.MaxIterations = 99
+	bad.DualTolerance = 0
+	if err := simp.SetParams(bad); !errors.Is(err, clp.ErrInvalidArgument) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrInvalidArgument, err)
+	}
+	if act := simp.Params(); act != p {
+		t.Fatalf("Expected %+v but saw %+v", p, act)
+	}
+
+	// The model should still solve.
+	simp = transportProblem()
+	if err := simp.SetParams(p); err != nil {
+		t.Fatal(err)
+	}
+	if st := simp.Dual(clp.NoValuesPass, clp.NoStartFinishOptions); st != clp.Optimal {
+		t.Fatalf("Expected %v but saw %v", clp.Optimal, st)
+	}
+}
+
+// Test if we can read solver parameters from a file.
+func TestParamsFromFile(t *testing.T) {
+	fName := writeTempFile(t, "clp-*.params", `# Tuned for hard instances
+maxIterations = 5000
+DualTolerance=1e-8
+primalWeight = 1e6
+
+maxFactor = 100
+`)
+	defer os.Remove(fName)
+	p, err := clp.ParamsFromFile(fName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := clp.NewSimplex().Params()
+	exp.MaxIterations = 5000
+	exp.DualTolerance = 1e-8
+	exp.InfeasibilityCost = 1e6
+	exp.FactorizationFrequency = 100
+	if p != exp {
+		t.Fatalf("Expected %+v but saw %+v", exp, p)
+	}
+
+	// Malformed files and invalid values should be rejected.
+	for _, contents := range []string{
+		"maxIterations 5000\n",
+		"noSuchParameter = 1\n",
+		"maxIterations = many\n",
+		"primalTolerance = -1\n",
+	} {
+		bName := writeTempFile(t, "clp-*.params", contents)
+		_, err = clp.ParamsFromFile(bName)
+		os.Remove(bName)
+		if !errors.Is(err, clp.ErrInvalidArgument) {
+			t.Fatalf("Expected %v for %q but saw %v", clp.ErrInvalidArgument, contents, err)
+		}
+	}
+
+	// A missing file should be reported as an I/O error.
+	if _, err = clp.ParamsFromFile(fName + ".missing"); !errors.Is(err, clp.ErrIO) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIO, err)
+	}
+}
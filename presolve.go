@@ -0,0 +1,257 @@
+// Presolve and postsolve
+
+package clp
+
+// #include "clp-interface.h"
+import "C"
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+)
+
+// PresolveOptions configures Presolve.  The zero value requests CLP's
+// defaults.
+type PresolveOptions struct {
+	Passes               int     // Maximum number of presolve passes, or 0 for the default of 5
+	FeasibilityTolerance float64 // Tolerance for declaring the problem infeasible, or 0 for the model's primal tolerance
+	DropNames            bool    // Whether to omit row and column names from the reduced model
+}
+
+// PresolveStats counts the rows, columns, and elements that Presolve removed.
+// CLP does not report why it removed each row and column, so Presolve
+// classifies them by inspecting the original model, counting each in the
+// first of the listed categories that applies.  The counts for each category
+// sum to RowsRemoved or ColumnsRemoved.
+type PresolveStats struct {
+	RowsRemoved      int // Total number of rows removed
+	EmptyRows        int // Removed rows with no nonzero elements
+	FreeRows         int // Removed rows with no finite bound
+	SingletonRows    int // Removed rows with a single nonzero element, which became a column bound
+	DuplicateRows    int // Removed rows whose elements match those of another row
+	OtherRows        int // Rows removed for other reasons, such as doubleton or forcing rows
+	ColumnsRemoved   int // Total number of columns removed
+	FixedColumns     int // Removed columns whose lower and upper bounds are equal
+	EmptyColumns     int // Removed columns with no nonzero elements
+	DuplicateColumns int // Removed columns whose elements match those of another column
+	OtherColumns     int // Columns removed for other reasons, such as implied-free or dominated columns
+	ElementsRemoved  int // Number of nonzero matrix elements removed
+}
+
+// A PresolveInfo records how Presolve reduced a model so that Postsolve can
+// map the reduced model's solution back to the original model.
+type PresolveInfo struct {
+	OriginalRows    []int         // Row of the original model corresponding to each row of the reduced model
+	OriginalColumns []int         // Column of the original model corresponding to each column of the reduced model
+	Stats           PresolveStats // Counts of what was removed
+
+	presolve *C.clp_object // Pointer to a ClpPresolve
+	orig     *Simplex      // Model that was presolved
+	reduced  *Simplex      // Model that Presolve returned
+}
+
+// Presolve simplifies a model by removing fixed variables, singleton and
+// redundant rows, duplicate columns, and other structures that CLP can
+// eliminate without solving, and returns the reduced model, which is often
+// much faster to solve, along with a PresolveInfo describing the reduction.
+// The original model is unchanged.  After solving the reduced model, call
+// the PresolveInfo's Postsolve method to recover a solution to the original
+// model.  The reduced model has no event or log handler.  Presolve returns an
+// error wrapping ErrInfeasibleOrUnbounded if it determines that the problem
+// has no optimal solution, ErrInvalidArgument if opts is invalid, and
+// ErrException if CLP throws an exception.
+func (s *Simplex) Presolve(opts PresolveOptions) (*Simplex, *PresolveInfo, error) {
+	// Check and apply defaults to the options.
+	if opts.Passes < 0 {
+		return nil, nil, fmt.Errorf("%w: Simplex.Presolve given %d passes", ErrInvalidArgument, opts.Passes)
+	}
+	if !(opts.FeasibilityTolerance >= 0) || math.IsInf(opts.FeasibilityTolerance, 1) {
+		return nil, nil, fmt.Errorf("%w: Simplex.Presolve given feasibility tolerance %v", ErrInvalidArgument, opts.FeasibilityTolerance)
+	}
+	passes := opts.Passes
+	if passes == 0 {
+		passes = defaultPresolvePasses
+	}
+	var dropNames C.int
+	if opts.DropNames {
+		dropNames = 1
+	}
+
+	// Presolve the model.
+	var cErr C.clp_error
+	pre := C.new_presolve(&cErr)
+	if err := cError("Simplex.Presolve", &cErr); err != nil {
+		return nil, nil, err
+	}
+	info := &PresolveInfo{presolve: pre, orig: s}
+	runtime.SetFinalizer(info, func(info *PresolveInfo) {
+		// Free the ClpPresolve.  There is no one to whom to report an
+		// error.
+		info.free()
+	})
+	model := C.presolve_model(pre, s.model, C.double(opts.FeasibilityTolerance), C.int(passes), dropNames, &cErr)
+	if err := cError("Simplex.Presolve", &cErr); err != nil {
+		info.free()
+		return nil, nil, err
+	}
+	if model == nil {
+		info.free()
+		return nil, nil, fmt.Errorf("%w: Simplex.Presolve found no optimal solution", ErrInfeasibleOrUnbounded)
+	}
+	info.reduced = wrapSimplex(model)
+
+	// Record the mapping from the reduced model to the original.
+	nr, nc := info.reduced.Dims()
+	rows := cMalloc(nr+1, C.int(0))
+	defer cFree(rows)
+	cols := cMalloc(nc+1, C.int(0))
+	defer cFree(cols)
+	C.presolve_original_indices(pre, C.int(nr), (*C.int)(rows), C.int(nc), (*C.int)(cols), &cErr)
+	if err := cError("Simplex.Presolve", &cErr); err != nil {
+		info.free()
+		return nil, nil, err
+	}
+	info.OriginalRows = make([]int, nr)
+	copyIntsCGo(info.OriginalRows, cIntSlice(rows, nr))
+	info.OriginalColumns = make([]int, nc)
+	copyIntsCGo(info.OriginalColumns, cIntSlice(cols, nc))
+	info.Stats = s.presolveStats(info)
+	return info.reduced, info, nil
+}
+
+// Postsolve maps the solution and basis of the reduced model returned by
+// Presolve back to orig, which must be the model that was presolved and must
+// not have been modified since.  The reduced model should have been solved
+// first.  A subsequent call to Primal on orig, which typically takes few or
+// no iterations, verifies optimality and cleans up any small infeasibilities
+// that postsolve introduced.  Neither model may be closed before Postsolve
+// is called.  Postsolve can be called only once and returns an error
+// wrapping ErrInvalidArgument if it is called again, given the wrong model,
+// or either model has been closed, or ErrException if CLP throws an
+// exception.
+func (info *PresolveInfo) Postsolve(orig *Simplex) error {
+	switch {
+	case info.presolve == nil:
+		return fmt.Errorf("%w: PresolveInfo.Postsolve was already called", ErrInvalidArgument)
+	case orig != info.orig:
+		return fmt.Errorf("%w: PresolveInfo.Postsolve given a model other than the one that was presolved", ErrInvalidArgument)
+	case orig.model == nil:
+		return fmt.Errorf("%w: PresolveInfo.Postsolve given a closed model", ErrInvalidArgument)
+	case info.reduced.model == nil:
+		return fmt.Errorf("%w: PresolveInfo.Postsolve called after the reduced model was closed", ErrInvalidArgument)
+	}
+	var cErr C.clp_error
+	C.postsolve(info.presolve, &cErr)
+	err := cError("PresolveInfo.Postsolve", &cErr)
+	info.free()
+	return err
+}
+
+// free frees the ClpPresolve underlying a PresolveInfo and releases the
+// models it refers to.
+func (info *PresolveInfo) free() {
+	if info.presolve == nil {
+		return
+	}
+	var cErr C.clp_error
+	C.free_presolve(info.presolve, &cErr)
+	cError("PresolveInfo.free", &cErr)
+	info.presolve = nil
+	info.orig = nil
+	info.reduced = nil
+}
+
+// presolveStats classifies the rows and columns of s that are missing from
+// the reduced model described by info.
+func (s *Simplex) presolveStats(info *PresolveInfo) PresolveStats {
+	var st PresolveStats
+	nr, nc := s.Dims()
+	starts, lengths, indices, elements := s.Matrix().SparseData()
+	rStarts, rLengths, rIndices, rElements := reverseSparseData(nr, starts, lengths, indices, elements)
+	_, redLengths, _, _ := info.reduced.Matrix().SparseData()
+	for _, n := range lengths {
+		st.ElementsRemoved += n
+	}
+	for _, n := range redLengths {
+		st.ElementsRemoved -= n
+	}
+
+	// Classify the removed columns.
+	kept := make([]bool, nc)
+	for _, c := range info.OriginalColumns {
+		kept[c] = true
+	}
+	colKeys := vectorKeys(starts, lengths, indices, elements)
+	cb := s.ColumnBounds()
+	for c, k := range kept {
+		if k {
+			continue
+		}
+		st.ColumnsRemoved++
+		switch {
+		case cb[c].Lower == cb[c].Upper:
+			st.FixedColumns++
+		case lengths[c] == 0:
+			st.EmptyColumns++
+		case colKeys[c].dup:
+			st.DuplicateColumns++
+		default:
+			st.OtherColumns++
+		}
+	}
+
+	// Classify the removed rows.
+	kept = make([]bool, nr)
+	for _, r := range info.OriginalRows {
+		kept[r] = true
+	}
+	rowKeys := vectorKeys(rStarts, rLengths, rIndices, rElements)
+	rb := s.RowBounds()
+	for r, k := range kept {
+		if k {
+			continue
+		}
+		st.RowsRemoved++
+		switch {
+		case rLengths[r] == 0:
+			st.EmptyRows++
+		case math.IsInf(rb[r].Lower, -1) && math.IsInf(rb[r].Upper, 1):
+			st.FreeRows++
+		case rLengths[r] == 1:
+			st.SingletonRows++
+		case rowKeys[r].dup:
+			st.DuplicateRows++
+		default:
+			st.OtherRows++
+		}
+	}
+	return st
+}
+
+// A vectorKey identifies a sparse vector by its contents.
+type vectorKey struct {
+	key string // Encoding of the vector's indices and elements
+	dup bool   // Whether another vector has the same key
+}
+
+// vectorKeys computes a vectorKey for each vector in sparse data.
+func vectorKeys(starts, lengths, indices []int, elements []float64) []vectorKey {
+	keys := make([]vectorKey, len(starts))
+	count := make(map[string]int, len(starts))
+	for i, st := range starts {
+		var buf []byte
+		for k := st; k < st+lengths[i]; k++ {
+			buf = strconv.AppendInt(buf, int64(indices[k]), 10)
+			buf = append(buf, ':')
+			buf = strconv.AppendFloat(buf, elements[k], 'g', -1, 64)
+			buf = append(buf, ' ')
+		}
+		keys[i].key = string(buf)
+		count[keys[i].key]++
+	}
+	for i := range keys {
+		keys[i].dup = count[keys[i].key] > 1
+	}
+	return keys
+}
@@ -0,0 +1,147 @@
+// Test presolve and postsolve
+
+package clp_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/lanl/clp"
+)
+
+// presolvableProblem returns an unsolved simplex model with structures that
+// presolve can remove: Minimize 3x + y + 2z + w subject to {x + y + z ≥ 4,
+// y ≤ 3, x + z free, y − w ≤ 1} with x fixed at 2.
+func presolvableProblem() *clp.Simplex {
+	inf := math.Inf(1)
+	mat := clp.NewPackedMatrix()
+	mat.AppendColumn([]clp.Nonzero{
+		{Index: 0, Value: 1.0}, // x
+		{Index: 2, Value: 1.0}, // x
+	})
+	mat.AppendColumn([]clp.Nonzero{
+		{Index: 0, Value: 1.0}, // y
+		{Index: 1, Value: 1.0}, // y
+		{Index: 3, Value: 1.0}, // y
+	})
+	mat.AppendColumn([]clp.Nonzero{
+		{Index: 0, Value: 1.0}, // z
+		{Index: 2, Value: 1.0}, // z
+	})
+	mat.AppendColumn([]clp.Nonzero{
+		{Index: 3, Value: -1.0}, // -w
+	})
+	cb := []clp.Bounds{
+		{Lower: 2, Upper: 2},   // x = 2
+		{Lower: 0, Upper: inf}, // y ≥ 0
+		{Lower: 0, Upper: inf}, // z ≥ 0
+		{Lower: 0, Upper: inf}, // w ≥ 0
+	}
+	rb := []clp.Bounds{
+		{Lower: 4, Upper: inf},    // x + y + z ≥ 4
+		{Lower: -inf, Upper: 3},   // y ≤ 3
+		{Lower: -inf, Upper: inf}, // x + z free
+		{Lower: -inf, Upper: 1},   // y − w ≤ 1
+	}
+	obj := []float64{3.0, 1.0, 2.0, 1.0} // 3x + y + 2z + w
+	simp := clp.NewSimplex()
+	simp.LoadProblem(mat, cb, obj, rb, nil)
+	simp.SetOptimizationDirection(clp.Minimize)
+	return simp
+}
+
+// Test if solving a presolved model and postsolving yields the same optimum
+// as solving the original model directly.
+func TestPresolve(t *testing.T) {
+	exp := presolvableProblem()
+	exp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+	obj := exp.ObjectiveValue()
+
+	// Presolve the model and check the statistics.
+	simp := presolvableProblem()
+	reduced, info, err := simp.Presolve(clp.PresolveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nr, nc := reduced.Dims()
+	if len(info.OriginalRows) != nr || len(info.OriginalColumns) != nc {
+		t.Fatalf("Expected %d original rows and %d original columns but saw %v and %v",
+			nr, nc, info.OriginalRows, info.OriginalColumns)
+	}
+	st := info.Stats
+	if st.RowsRemoved != 4-nr || st.ColumnsRemoved != 4-nc {
+		t.Fatalf("Expected %d rows and %d columns removed but saw %+v", 4-nr, 4-nc, st)
+	}
+	if n := st.EmptyRows + st.FreeRows + st.SingletonRows + st.DuplicateRows + st.OtherRows; n != st.RowsRemoved {
+		t.Fatalf("Expected row categories to sum to %d but saw %+v", st.RowsRemoved, st)
+	}
+	if n := st.FixedColumns + st.EmptyColumns + st.DuplicateColumns + st.OtherColumns; n != st.ColumnsRemoved {
+		t.Fatalf("Expected column categories to sum to %d but saw %+v", st.ColumnsRemoved, st)
+	}
+	if st.FixedColumns != 1 || st.RowsRemoved < 2 {
+		t.Fatalf("Expected a fixed column and at least two rows removed but saw %+v", st)
+	}
+
+	// Solve the reduced model and map the solution back.
+	if s := reduced.Primal(clp.NoValuesPass, clp.NoStartFinishOptions); s != clp.Optimal {
+		t.Fatalf("Expected %v but saw %v", clp.Optimal, s)
+	}
+	if err := info.Postsolve(simp); err != nil {
+		t.Fatal(err)
+	}
+	if s := simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions); s != clp.Optimal {
+		t.Fatalf("Expected %v but saw %v", clp.Optimal, s)
+	}
+	if v := simp.ObjectiveValue(); !closeTo(v, obj, 1e-6) {
+		t.Fatalf("Expected objective %v but saw %v", obj, v)
+	}
+	if soln := simp.PrimalColumnSolution(); len(soln) != 4 || !closeTo(soln[0], 2.0, 1e-6) {
+		t.Fatalf("Expected four columns with x = 2 but saw %v", soln)
+	}
+
+	// Postsolve can be called only once.
+	if err := info.Postsolve(simp); !errors.Is(err, clp.ErrInvalidArgument) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrInvalidArgument, err)
+	}
+}
+
+// Test if Presolve rejects bad options, the wrong model, and infeasible
+// problems.
+func TestPresolveErrors(t *testing.T) {
+	simp := presolvableProblem()
+	_, _, err := simp.Presolve(clp.PresolveOptions{Passes: -1})
+	if !errors.Is(err, clp.ErrInvalidArgument) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrInvalidArgument, err)
+	}
+	_, info, err := simp.Presolve(clp.PresolveOptions{DropNames: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := info.Postsolve(presolvableProblem()); !errors.Is(err, clp.ErrInvalidArgument) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrInvalidArgument, err)
+	}
+
+	// Postsolve must not touch a reduced model that was closed.
+	reduced, info, err := simp.Presolve(clp.PresolveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reduced.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := info.Postsolve(simp); !errors.Is(err, clp.ErrInvalidArgument) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrInvalidArgument, err)
+	}
+
+	// Minimize y subject to y ≥ 5 with 0 ≤ y ≤ 1.
+	mat := clp.NewPackedMatrix()
+	mat.AppendColumn([]clp.Nonzero{{Index: 0, Value: 1.0}})
+	infeas := clp.NewSimplex()
+	infeas.LoadProblem(mat, []clp.Bounds{{Lower: 0, Upper: 1}}, []float64{1.0},
+		[]clp.Bounds{{Lower: 5, Upper: math.Inf(1)}}, nil)
+	_, _, err = infeas.Presolve(clp.PresolveOptions{})
+	if !errors.Is(err, clp.ErrInfeasibleOrUnbounded) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrInfeasibleOrUnbounded, err)
+	}
+}
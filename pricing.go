@@ -0,0 +1,112 @@
+// Pricing strategies
+
+package clp
+
+// #include "clp-interface.h"
+import "C"
+import "fmt"
+
+// A Pricing selects how the primal or dual simplex method chooses the
+// variable to enter or leave the basis at each iteration.
+type Pricing int
+
+// These constants are the possible values for a Pricing.  Not every strategy
+// applies to both methods.
+const (
+	AutoPricing     Pricing = 0 // CLP's default, which switches between strategies as the solve progresses
+	Dantzig                 = 1 // Choose the most negative reduced cost or most infeasible row; cheap per iteration but may take many iterations
+	Steepest                = 2 // Full steepest-edge pricing; expensive per iteration but usually takes few iterations
+	Devex                   = 3 // Exact devex, an approximation to steepest edge (primal only)
+	PartialPricing          = 4 // Price only part of the columns at each iteration (primal only)
+	PartialSteepest         = 5 // Steepest-edge pricing over part of the rows at each iteration (dual only)
+)
+
+// String returns the name of a Pricing.
+func (p Pricing) String() string {
+	switch p {
+	case AutoPricing:
+		return "AutoPricing"
+	case Dantzig:
+		return "Dantzig"
+	case Steepest:
+		return "Steepest"
+	case Devex:
+		return "Devex"
+	case PartialPricing:
+		return "PartialPricing"
+	case PartialSteepest:
+		return "PartialSteepest"
+	default:
+		return fmt.Sprintf("Pricing(%d)", int(p))
+	}
+}
+
+// SetPrimalPricing selects the pricing strategy used by Primal and related
+// solves.  It accepts AutoPricing, Dantzig, Steepest, Devex, and
+// PartialPricing, which correspond to CLP's ClpPrimalColumnSteepest in modes
+// 3, 1, 0, and 4 and to ClpPrimalColumnDantzig.  SetPrimalPricing returns an
+// error wrapping ErrInvalidArgument for any other value and an error wrapping
+// ErrException if CLP throws an exception.
+func (s *Simplex) SetPrimalPricing(p Pricing) error {
+	var mode C.int
+	switch p {
+	case AutoPricing:
+		mode = 3
+	case Dantzig:
+		mode = -1
+	case Steepest:
+		mode = 1
+	case Devex:
+		mode = 0
+	case PartialPricing:
+		mode = 4
+	default:
+		return fmt.Errorf("%w: Simplex.SetPrimalPricing does not support %v", ErrInvalidArgument, p)
+	}
+	var cErr C.clp_error
+	C.simplex_set_primal_pricing(s.model, mode, &cErr)
+	if err := cError("Simplex.SetPrimalPricing", &cErr); err != nil {
+		return err
+	}
+	s.primalPricing = p
+	return nil
+}
+
+// PrimalPricing returns the pricing strategy used by Primal.
+func (s *Simplex) PrimalPricing() Pricing {
+	return s.primalPricing
+}
+
+// SetDualPricing selects the pricing strategy used by Dual and related
+// solves.  It accepts AutoPricing, Dantzig, Steepest, and PartialSteepest,
+// which correspond to CLP's ClpDualRowSteepest in modes 3, 1, and 2 and to
+// ClpDualRowDantzig.  SetDualPricing returns an error wrapping
+// ErrInvalidArgument for any other value and an error wrapping ErrException
+// if CLP throws an exception.
+func (s *Simplex) SetDualPricing(p Pricing) error {
+	var mode C.int
+	switch p {
+	case AutoPricing:
+		mode = 3
+	case Dantzig:
+		mode = -1
+	case Steepest:
+		mode = 1
+	case PartialSteepest:
+		mode = 2
+	default:
+		return fmt.Errorf("%w: Simplex.SetDualPricing does not support %v", ErrInvalidArgument, p)
+	}
+	var cErr C.clp_error
+	C.simplex_set_dual_pricing(s.model, mode, &cErr)
+	if err := cError("Simplex.SetDualPricing", &cErr); err != nil {
+		return err
+	}
+	s.dualPricing = p
+	return nil
+}
+
+// DualPricing returns the pricing strategy used by Dual.
+func (s *Simplex) DualPricing() Pricing {
+	return s.dualPricing
+}
@@ -0,0 +1,110 @@
+// Test pricing strategies
+
+package clp_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/lanl/clp"
+)
+
+// primalPricings and dualPricings list the strategies each method supports.
+var (
+	primalPricings = []clp.Pricing{clp.AutoPricing, clp.Dantzig, clp.Steepest, clp.Devex, clp.PartialPricing}
+	dualPricings   = []clp.Pricing{clp.AutoPricing, clp.Dantzig, clp.Steepest, clp.PartialSteepest}
+)
+
+// Test if every pricing strategy finds the same optimum.
+func TestPricing(t *testing.T) {
+	exp := transportProblem()
+	exp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+	obj := exp.ObjectiveValue()
+	for _, p := range primalPricings {
+		simp := transportProblem()
+		if err := simp.SetPrimalPricing(p); err != nil {
+			t.Fatal(err)
+		}
+		if simp.PrimalPricing() != p {
+			t.Fatalf("Expected primal pricing %v but saw %v", p, simp.PrimalPricing())
+		}
+		if st := simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions); st != clp.Optimal {
+			t.Fatalf("Expected %v with primal pricing %v but saw %v", clp.Optimal, p, st)
+		}
+		if v := simp.ObjectiveValue(); !closeTo(v, obj, 1e-6*math.Abs(obj)) {
+			t.Fatalf("Expected %v with primal pricing %v but saw %v", obj, p, v)
+		}
+	}
+	for _, p := range dualPricings {
+		simp := transportProblem()
+		if err := simp.SetDualPricing(p); err != nil {
+			t.Fatal(err)
+		}
+		if simp.DualPricing() != p {
+			t.Fatalf("Expected dual pricing %v but saw %v", p, simp.DualPricing())
+		}
+		if st := simp.Dual(clp.NoValuesPass, clp.NoStartFinishOptions); st != clp.Optimal {
+			t.Fatalf("Expected %v with dual pricing %v but saw %v", clp.Optimal, p, st)
+		}
+		if v := simp.ObjectiveValue(); !closeTo(v, obj, 1e-6*math.Abs(obj)) {
+			t.Fatalf("Expected %v with dual pricing %v but saw %v", obj, p, v)
+		}
+	}
+
+	// Strategies that don't apply to a method should be rejected.
+	simp := clp.NewSimplex()
+	if err := simp.SetPrimalPricing(clp.PartialSteepest); !errors.Is(err, clp.ErrInvalidArgument) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrInvalidArgument, err)
+	}
+	if err := simp.SetDualPricing(clp.Devex); !errors.Is(err, clp.ErrInvalidArgument) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrInvalidArgument, err)
+	}
+	if simp.PrimalPricing() != clp.AutoPricing || simp.DualPricing() != clp.AutoPricing {
+		t.Fatalf("Expected %v but saw %v and %v", clp.AutoPricing, simp.PrimalPricing(), simp.DualPricing())
+	}
+}
+
+// pricingProblems are the unsolved test problems over which the pricing
+// benchmarks run.
+var pricingProblems = []struct {
+	name string
+	make func() *clp.Simplex
+}{
+	{"transport", transportProblem},
+	{"large", func() *clp.Simplex { return largeModel(10000) }},
+}
+
+// Benchmark the primal method with each pricing strategy.
+func BenchmarkPrimalPricing(b *testing.B) {
+	for _, prob := range pricingProblems {
+		for _, p := range primalPricings {
+			b.Run(prob.name+"/"+p.String(), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					simp := prob.make()
+					simp.SetPrimalPricing(p)
+					b.StartTimer()
+					simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+				}
+			})
+		}
+	}
+}
+
+// Benchmark the dual method with each pricing strategy.
+func BenchmarkDualPricing(b *testing.B) {
+	for _, prob := range pricingProblems {
+		for _, p := range dualPricings {
+			b.Run(prob.name+"/"+p.String(), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					simp := prob.make()
+					simp.SetDualPricing(p)
+					b.StartTimer()
+					simp.Dual(clp.NoValuesPass, clp.NoStartFinishOptions)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Solve results
+
+package clp
+
+// #include "clp-interface.h"
+import "C"
+import (
+	"fmt"
+	"time"
+)
+
+// A Result summarizes the outcome of a solve.
+type Result struct {
+	Status                   SimplexStatus   // Primary status returned by the solve
+	Secondary                SecondaryStatus // Secondary status
+	Objective                float64         // Value of the objective function
+	Iterations               int             // Number of iterations performed
+	PrimalInfeasibilities    int             // Number of primal infeasibilities
+	SumPrimalInfeasibilities float64         // Sum of primal infeasibilities
+	DualInfeasibilities      int             // Number of dual infeasibilities
+	SumDualInfeasibilities   float64         // Sum of dual infeasibilities
+	WallTime                 time.Duration   // Wall-clock time taken by the solve
+}
+
+// String summarizes a Result on a single line.
+func (r Result) String() string {
+	return fmt.Sprintf("%v (%v): objective %v after %d iterations in %v; %d primal infeasibilities summing to %v; %d dual infeasibilities summing to %v",
+		r.Status, r.Secondary, r.Objective, r.Iterations, r.WallTime,
+		r.PrimalInfeasibilities, r.SumPrimalInfeasibilities,
+		r.DualInfeasibilities, r.SumDualInfeasibilities)
+}
+
+// Result reports on the most recent solve by any method.  InitialSolve and
+// the Result and Context variants of the solve methods also return the
+// Result directly.  Before the first solve, Result returns a Result whose
+// status is NotSolved and whose other fields are zero.  If CLP throws an
+// exception while gathering the statistics, Result returns only the status
+// and wall time, and Err reports the exception.
+func (s *Simplex) Result() Result {
+	if !s.solved {
+		return Result{Status: NotSolved}
+	}
+	r := Result{
+		Status:    s.status,
+		Secondary: s.secondaryStatus(),
+		WallTime:  s.solveTime,
+	}
+	var obj, sumPrimalInf, sumDualInf C.double
+	var iters, nPrimalInf, nDualInf C.int
+	var cErr C.clp_error
+	C.simplex_get_solve_stats(s.model, &obj, &iters,
+		&nPrimalInf, &sumPrimalInf, &nDualInf, &sumDualInf, &cErr)
+	if s.recordErr(cError("Simplex.Result", &cErr)) {
+		return r
+	}
+	r.Objective = float64(obj)
+	r.Iterations = int(iters)
+	r.PrimalInfeasibilities = int(nPrimalInf)
+	r.SumPrimalInfeasibilities = float64(sumPrimalInf)
+	r.DualInfeasibilities = int(nDualInf)
+	r.SumDualInfeasibilities = float64(sumDualInf)
+	return r
+}
+
+// PrimalResult is like Primal but returns a Result describing the solve.  If
+// CLP throws an exception, the Result has status StoppedOnErrors, and
+// PrimalResult returns an error describing the exception.
+func (s *Simplex) PrimalResult(vp ValuesPass, sfo StartFinishOptions) (Result, error) {
+	_, err := s.primal(vp, sfo)
+	return s.Result(), err
+}
+
+// DualResult is like Dual but returns a Result describing the solve.  If CLP
+// throws an exception, the Result has status StoppedOnErrors, and DualResult
+// returns an error describing the exception.
+func (s *Simplex) DualResult(vp ValuesPass, sfo StartFinishOptions) (Result, error) {
+	_, err := s.dual(vp, sfo)
+	return s.Result(), err
+}
+
+// BarrierResult is like Barrier but returns a Result describing the solve.
+// If CLP throws an exception, the Result has status StoppedOnErrors, and
+// BarrierResult returns an error describing the exception.
+func (s *Simplex) BarrierResult(xover bool) (Result, error) {
+	_, err := s.barrier(xover)
+	return s.Result(), err
+}
+
+// ReducedGradientResult is like ReducedGradient but returns a Result
+// describing the solve.  If CLP throws an exception, the Result has status
+// StoppedOnErrors, and ReducedGradientResult returns an error describing the
+// exception.
+func (s *Simplex) ReducedGradientResult(phase bool) (Result, error) {
+	_, err := s.reducedGradient(phase)
+	return s.Result(), err
+}
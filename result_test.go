@@ -0,0 +1,105 @@
+// Test solve results
+
+package clp_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lanl/clp"
+)
+
+// Test if Result reports the outcome of an optimal solve.
+func TestResultOptimal(t *testing.T) {
+	simp := transportProblem()
+	st := simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+	r := simp.Result()
+	if r.Status != st || r.Status != clp.Optimal {
+		t.Fatalf("Expected status %v but saw %v", clp.Optimal, r.Status)
+	}
+	if r.Secondary != clp.SecondaryNone {
+		t.Fatalf("Expected secondary status %v but saw %v", clp.SecondaryNone, r.Secondary)
+	}
+	if r.Objective != simp.ObjectiveValue() {
+		t.Fatalf("Expected objective %v but saw %v", simp.ObjectiveValue(), r.Objective)
+	}
+	if r.Iterations <= 0 || r.Iterations != simp.NumberIterations() {
+		t.Fatalf("Expected %d iterations but saw %d", simp.NumberIterations(), r.Iterations)
+	}
+	if r.PrimalInfeasibilities != 0 || r.DualInfeasibilities != 0 {
+		t.Fatalf("Expected no infeasibilities but saw %d primal and %d dual",
+			r.PrimalInfeasibilities, r.DualInfeasibilities)
+	}
+	if r.WallTime <= 0 {
+		t.Fatalf("Expected a positive wall time but saw %v", r.WallTime)
+	}
+}
+
+// Test if Result reports the infeasibilities of an infeasible problem.
+func TestResultInfeasible(t *testing.T) {
+	// Require x ≥ 0 and x ≤ −1.
+	mat := clp.NewPackedMatrix()
+	mat.AppendColumn([]clp.Nonzero{{Index: 0, Value: 1.0}})
+	simp := clp.NewSimplex()
+	simp.LoadProblem(mat, nil, []float64{1.0}, []clp.Bounds{{Lower: math.Inf(-1), Upper: -1}}, nil)
+	simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+	r := simp.Result()
+	if r.Status != clp.Infeasible {
+		t.Fatalf("Expected status %v but saw %v", clp.Infeasible, r.Status)
+	}
+	if r.PrimalInfeasibilities <= 0 || r.SumPrimalInfeasibilities <= 0 {
+		t.Fatalf("Expected primal infeasibilities but saw %d summing to %v",
+			r.PrimalInfeasibilities, r.SumPrimalInfeasibilities)
+	}
+}
+
+// Test if the Result variants of the solve methods agree with Result and if
+// Result reports NotSolved before the first solve.
+func TestSolveResults(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		solve func(*clp.Simplex) (clp.Result, error)
+	}{
+		{"PrimalResult", func(s *clp.Simplex) (clp.Result, error) {
+			return s.PrimalResult(clp.NoValuesPass, clp.NoStartFinishOptions)
+		}},
+		{"DualResult", func(s *clp.Simplex) (clp.Result, error) {
+			return s.DualResult(clp.NoValuesPass, clp.NoStartFinishOptions)
+		}},
+	} {
+		simp := transportProblem()
+		if r := simp.Result(); r.Status != clp.NotSolved || r.Iterations != 0 {
+			t.Fatalf("%s: Expected an unsolved Result before solving but saw %v", c.name, r)
+		}
+		r, err := c.solve(simp)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if r.Status != clp.Optimal || r.Iterations <= 0 {
+			t.Fatalf("%s: Expected an optimal solve but saw %v", c.name, r)
+		}
+		if r2 := simp.Result(); r2.Status != r.Status || r2.Objective != r.Objective || r2.Iterations != r.Iterations {
+			t.Fatalf("%s: Expected %v but Result returned %v", c.name, r, r2)
+		}
+	}
+}
+
+// Test if statuses have readable names.
+func TestStatusStrings(t *testing.T) {
+	for _, c := range []struct {
+		st  interface{ String() string }
+		exp string
+	}{
+		{clp.Optimal, "Optimal"},
+		{clp.SimplexStatus(clp.StoppedByEventHandler), "StoppedByEventHandler"},
+		{clp.SimplexStatus(clp.NotSolved), "NotSolved"},
+		{clp.SimplexStatus(42), "SimplexStatus(42)"},
+		{clp.SecondaryStatus(clp.SecondaryNone), "SecondaryNone"},
+		{clp.SecondaryStatus(clp.SecondaryStoppedByUser), "SecondaryStoppedByUser"},
+		{clp.SecondaryStatus(42), "SecondaryStatus(42)"},
+	} {
+		if act := c.st.String(); act != c.exp {
+			t.Fatalf("Expected %q but saw %q", c.exp, act)
+		}
+	}
+}
@@ -7,39 +7,99 @@ package clp
 import "C"
 import (
 	"fmt"
+	"math"
 	"runtime"
+	"time"
 	"unsafe"
 )
 
 // A Simplex represents solves linear-programming problems using the simplex
 // method.
 type Simplex struct {
-	model  *C.clp_object    // Pointer to a ClpSimplex
-	allocs []unsafe.Pointer // Row/column data to which the ClpSimplex points
-	matrix Matrix           // Currently loaded matrix, needed here to keep the C++ object live
+	model         *C.clp_object // Pointer to a ClpSimplex
+	matrix        Matrix        // Currently loaded matrix, needed here to keep the C++ object live
+	eventHandler  EventHandler  // Go function to invoke on solver events
+	eventHandle   uintptr       // Handle by which C refers to eventHandler
+	logHandle     uintptr       // Handle by which C refers to the log handler
+	err           error         // First CLP exception not returned by the method that caught it
+	strict        bool          // Whether to validate problems before loading them
+	status        SimplexStatus // Status returned by the most recent solve
+	solved        bool          // Whether any solve has been performed
+	solveTime     time.Duration // Wall time taken by the most recent solve
+	primalPricing Pricing       // Pricing strategy used by the primal method
+	dualPricing   Pricing       // Pricing strategy used by the dual method
 }
 
-// NewSimplex creates a new simplex model.
+// NewSimplex creates a new simplex model.  It panics if CLP cannot allocate
+// the model.
 func NewSimplex() *Simplex {
-	s := &Simplex{
-		model:  C.new_simplex_model(),
-		allocs: make([]unsafe.Pointer, 0, 64),
-		matrix: nil,
+	var cErr C.clp_error
+	model := C.new_simplex_model(&cErr)
+	if err := cError("NewSimplex", &cErr); err != nil {
+		panic(err)
 	}
+	return wrapSimplex(model)
+}
+
+// wrapSimplex wraps a Simplex around an existing ClpSimplex, which the
+// Simplex then owns.
+func wrapSimplex(model *C.clp_object) *Simplex {
+	s := &Simplex{model: model}
 	runtime.SetFinalizer(s, func(s *Simplex) {
-		// When we're finished with it, free the model and all the
-		// memory it referred to.
-		C.free_simplex_model(s.model)
-		for _, p := range s.allocs {
-			cFree(p)
-		}
-		s.model = nil
-		s.allocs = nil
-		s.matrix = nil
+		// When we're finished with it, free the model.  There is no
+		// one to whom to report an error.
+		s.free()
 	})
 	return s
 }
 
+// Close immediately frees the C++ objects underlying a simplex model instead
+// of waiting for the garbage collector to do so.  The model must not be used
+// after Close returns, but calling Close again is harmless.  Close returns an
+// error only if CLP throws an exception while freeing the model.
+func (s *Simplex) Close() error {
+	runtime.SetFinalizer(s, nil)
+	return s.free()
+}
+
+// free implements Close and the finalizer.
+func (s *Simplex) free() error {
+	if s.model == nil {
+		return nil
+	}
+	var cErr C.clp_error
+	C.free_simplex_model(s.model, &cErr)
+	unregisterHandle(s.eventHandle)
+	unregisterHandle(s.logHandle)
+	s.model = nil
+	s.matrix = nil
+	s.eventHandler = nil
+	s.eventHandle = 0
+	s.logHandle = 0
+	return cError("Simplex.Close", &cErr)
+}
+
+// Err returns the first error that CLP reported, by throwing a C++ exception,
+// during a Simplex method that cannot itself return an error, or nil if there
+// has been no such error.  Methods with an error result return CLP's
+// exceptions directly instead.  After an exception, the contents of the model
+// are unreliable.
+func (s *Simplex) Err() error {
+	return s.err
+}
+
+// recordErr saves err for Err to return unless an earlier error was already
+// saved.  It returns true if err is non-nil.
+func (s *Simplex) recordErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if s.err == nil {
+		s.err = err
+	}
+	return true
+}
+
 // Bounds represents the lower and upper bound on a value.
 type Bounds struct {
 	Lower float64
@@ -53,84 +113,551 @@ type Bounds struct {
 // function.  Any of these arguments except for the matrix can be nil.  When
 // nil, the column bounds default to {0, ∞} for each row; the column and row
 // objective functions default to 0 for all coefficients; and the row bounds
-// default to {−∞, +∞} for each column.
+// default to {−∞, +∞} for each column.  LoadProblem panics if the arguments
+// are invalid; see TryLoadProblem for a variant that returns an error
+// instead.
 func (s *Simplex) LoadProblem(m Matrix, cb []Bounds, obj []float64, rb []Bounds, rowObj []float64) {
-	// Because of the the way the C++ API works, m can't be an arbitrary
-	// implementation of the Matrix interface.  We therefore check that it
-	// wraps one of the interfaces CLP knows about and abort if not.
-	matrix, ok := m.(*PackedMatrix)
-	if !ok {
-		panic(fmt.Sprintf("clp: Simplex.LoadProblem cannot currently accept a Matrix of type %T", m))
+	if err := s.TryLoadProblem(m, cb, obj, rb, rowObj); err != nil {
+		panic(err)
+	}
+}
+
+// TryLoadProblem is like LoadProblem but returns an error instead of
+// panicking if the arguments are invalid.  The error wraps
+// ErrUnsupportedMatrix if m is nil or its SparseData is inconsistent with its
+// dimensions, ErrIndexOutOfRange if m contains a row index outside the
+// matrix, ErrDimensionMismatch if the lengths of the other arguments do not
+// match m's dimensions, and ErrException if CLP rejects the problem.  In
+// strict mode (see SetStrict), TryLoadProblem first checks its arguments with
+// Validate and returns the resulting *ValidationError, if any.
+func (s *Simplex) TryLoadProblem(m Matrix, cb []Bounds, obj []float64, rb []Bounds, rowObj []float64) error {
+	if m == nil {
+		return fmt.Errorf("%w: Simplex.LoadProblem requires a non-nil Matrix", ErrUnsupportedMatrix)
+	}
+	if s.strict {
+		if err := Validate(m, cb, obj, rb, rowObj); err != nil {
+			return err
+		}
 	}
-	s.matrix = m
 
 	// Get the matrix dimensions.
 	nr, nc := m.Dims()
 
 	if rb != nil && len(rb) != nr {
-		panic(fmt.Sprintf("clp: Simplex.LoadProblem incorrect number of row bounds %d vs %d", len(rb), nr))
+		return fmt.Errorf("%w: Simplex.LoadProblem incorrect number of row bounds %d vs %d", ErrDimensionMismatch, len(rb), nr)
 	}
 	if rowObj != nil && len(rowObj) != nr {
-		panic(fmt.Sprintf("clp: Simplex.LoadProblem incorrect length of row objective function %d vs %d", len(rowObj), nr))
+		return fmt.Errorf("%w: Simplex.LoadProblem incorrect length of row objective function %d vs %d", ErrDimensionMismatch, len(rowObj), nr)
 	}
 
 	if cb != nil && len(cb) != nc {
-		panic(fmt.Sprintf("clp: Simplex.LoadProblem incorrect number of column bounds %d vs %d", len(cb), nc))
+		return fmt.Errorf("%w: Simplex.LoadProblem incorrect number of column bounds %d vs %d", ErrDimensionMismatch, len(cb), nc)
 	}
 	if obj != nil && len(obj) != nc {
-		panic(fmt.Sprintf("clp: Simplex.LoadProblem incorrect length of objective function %d vs %d", len(obj), nc))
+		return fmt.Errorf("%w: Simplex.LoadProblem incorrect length of objective function %d vs %d", ErrDimensionMismatch, len(obj), nc)
 	}
 
 	// It's not safe to pass Go-allocated memory to C.  Hence, we use C's
-	// malloc to allocate the memory, which we free in the Simplex
-	// finalizer.  First, we convert cb to two C vectors, colLB and colUB.
-	var colLB, colUB unsafe.Pointer
-	if cb != nil {
-		colLB = cMalloc(nc, C.double(0.0))
-		colUB = cMalloc(nc, C.double(0.0))
-		for i, b := range cb {
-			cSetArrayDouble(colLB, i, b.Lower)
-			cSetArrayDouble(colUB, i, b.Upper)
+	// malloc to allocate the memory, which we free as soon as CLP has
+	// copied it.  nil arguments become NULL pointers, which tell CLP to
+	// use default values.  First, we convert cb to two C vectors, colLB
+	// and colUB.
+	colLB, colUB := cBounds(cb)
+	defer cFree(colLB)
+	defer cFree(colUB)
+
+	// Next, we convert obj to a C vector, cObj.
+	cObj := cDoubles(obj)
+	defer cFree(cObj)
+
+	// Then, we convert rb to two C vectors, rowLB and rowUB.
+	rowLB, rowUB := cBounds(rb)
+	defer cFree(rowLB)
+	defer cFree(rowUB)
+
+	// Finally, we convert rowObj to a C vector, rObj.
+	rObj := cDoubles(rowObj)
+	defer cFree(rObj)
+
+	// With all of our parameters ready, we can call our C wrapper
+	// function.  CLP can copy a PackedMatrix directly.  Any other Matrix
+	// is first exported to C arrays in compressed sparse column form.
+	var cErr C.clp_error
+	matrix, isPacked := m.(*PackedMatrix)
+	if isPacked {
+		C.simplex_load_problem(s.model, matrix.matrix,
+			(*C.double)(colLB), (*C.double)(colUB), (*C.double)(cObj),
+			(*C.double)(rowLB), (*C.double)(rowUB), (*C.double)(rObj), &cErr)
+	} else {
+		starts, indices, elements, err := cColumnMajor(m, nr, nc)
+		if err != nil {
+			return err
 		}
-		s.allocs = append(s.allocs, colLB, colUB)
+		defer cFree(starts)
+		defer cFree(indices)
+		defer cFree(elements)
+		C.simplex_load_problem_csc(s.model, C.int(nc), C.int(nr),
+			(*C.int)(starts), (*C.int)(indices), (*C.double)(elements),
+			(*C.double)(colLB), (*C.double)(colUB), (*C.double)(cObj),
+			(*C.double)(rowLB), (*C.double)(rowUB), (*C.double)(rObj), &cErr)
+	}
+	if err := cError("Simplex.LoadProblem", &cErr); err != nil {
+		return err
 	}
+	s.matrix = m
 
-	// Next, we convert obj to a C vector, cObj.
-	var cObj unsafe.Pointer
-	if obj != nil {
-		cObj = cMalloc(nc, C.double(0.0))
-		for i, v := range obj {
-			cSetArrayDouble(cObj, i, v)
+	// Transfer any row and column names from the matrix to the model.
+	if isPacked && matrix.hasNames() {
+		if err := s.SetRowNames(matrix.RowNames()); err != nil {
+			return err
 		}
-		s.allocs = append(s.allocs, cObj)
+		return s.SetColumnNames(matrix.ColumnNames())
 	}
+	return nil
+}
 
-	// Then, we convert rb to two C vectors, rowLB and rowUB.
-	var rowLB, rowUB unsafe.Pointer
-	if rb != nil {
-		rowLB = cMalloc(nr, C.double(0.0))
-		rowUB = cMalloc(nr, C.double(0.0))
-		for i, b := range rb {
-			cSetArrayDouble(rowLB, i, b.Lower)
-			cSetArrayDouble(rowUB, i, b.Upper)
+// cColumnMajor converts an arbitrary Matrix with nr rows and nc columns to
+// the C arrays that CLP expects for loading a problem in compressed sparse
+// column form: the starting offset of each column (plus one final offset
+// marking the end of the last column), the row indices, and the elements.
+// Unlike the Matrix's own SparseData, the C arrays contain no gaps between
+// columns.  cColumnMajor returns an error if the SparseData is inconsistent
+// with the matrix's dimensions.  On success, the caller must free all three
+// arrays with cFree.
+func cColumnMajor(m Matrix, nr, nc int) (starts, indices, elements unsafe.Pointer, err error) {
+	// Ensure that the sparse data are self-consistent.
+	gStarts, gLengths, gIndices, gElements := m.SparseData()
+	if len(gStarts) != nc || len(gLengths) != nc || len(gIndices) != len(gElements) {
+		return nil, nil, nil, fmt.Errorf("%w: Simplex.LoadProblem given a %T with %d columns whose SparseData returned %d starts, %d lengths, %d indices, and %d elements",
+			ErrUnsupportedMatrix, m, nc, len(gStarts), len(gLengths), len(gIndices), len(gElements))
+	}
+	nnz := 0
+	for c, st := range gStarts {
+		n := gLengths[c]
+		if st < 0 || n < 0 || st+n > len(gIndices) {
+			return nil, nil, nil, fmt.Errorf("%w: Simplex.LoadProblem given a %T whose column %d spans [%d, %d) but only %d indices",
+				ErrUnsupportedMatrix, m, c, st, st+n, len(gIndices))
+		}
+		for _, r := range gIndices[st : st+n] {
+			if r < 0 || r >= nr {
+				return nil, nil, nil, fmt.Errorf("%w: Simplex.LoadProblem %s has row index %d, which is not in [0, %d)",
+					ErrIndexOutOfRange, matrixColumnLabel(m, c), r, nr)
+			}
 		}
-		s.allocs = append(s.allocs, rowLB, rowUB)
+		nnz += n
 	}
 
-	// Finally, we convert rowObj to a C vector, rObj.
-	var rObj unsafe.Pointer
-	if rowObj != nil {
-		rObj = cMalloc(nr, C.double(0.0))
-		for i, v := range rowObj {
-			cSetArrayDouble(rObj, i, v)
+	// Copy the data to C, closing up any gaps.
+	starts = cMalloc(nc+1, C.int(0))
+	indices = cMalloc(nnz+1, C.int(0))
+	elements = cMalloc(nnz+1, C.double(0.0))
+	ss := cIntSlice(starts, nc+1)
+	is := cIntSlice(indices, nnz)
+	es := cDoubleSlice(elements, nnz)
+	k := 0
+	for c, st := range gStarts {
+		n := gLengths[c]
+		ss[c] = C.int(k)
+		copyIntsGoC(is[k:k+n], gIndices[st:st+n])
+		copy(es[k:k+n], gElements[st:st+n])
+		k += n
+	}
+	ss[nc] = C.int(k)
+	return starts, indices, elements, nil
+}
+
+// matrixColumnLabel describes a column of a Matrix for an error message: its
+// number followed by its name if m is a PackedMatrix that was assigned
+// names.
+func matrixColumnLabel(m Matrix, col int) string {
+	if pm, ok := m.(*PackedMatrix); ok && pm.hasNames() {
+		return fmt.Sprintf("column %d (%s)", col, pm.ColumnName(col))
+	}
+	return fmt.Sprintf("column %d", col)
+}
+
+// cSparseVectors converts a list of sparse vectors to the C arrays that CLP
+// expects for adding rows or columns: the starting offset of each vector
+// (plus one final offset marking the end of the last vector), the indices,
+// and the elements.  It returns an error if any index lies outside [0, lim).
+// On success, the caller must free all three arrays with cFree.
+func cSparseVectors(vecs [][]Nonzero, lim int) (starts, indices, elements unsafe.Pointer, err error) {
+	nnz := 0
+	for _, v := range vecs {
+		for _, nz := range v {
+			if nz.Index < 0 || nz.Index >= lim {
+				return nil, nil, nil, fmt.Errorf("index %d is not in [0, %d)", nz.Index, lim)
+			}
+		}
+		nnz += len(v)
+	}
+	starts = cMalloc(len(vecs)+1, C.int(0))
+	indices = cMalloc(nnz+1, C.int(0))
+	elements = cMalloc(nnz+1, C.double(0.0))
+	ss := cIntSlice(starts, len(vecs)+1)
+	is := cIntSlice(indices, nnz)
+	es := cDoubleSlice(elements, nnz)
+	k := 0
+	for i, v := range vecs {
+		ss[i] = C.int(k)
+		for _, nz := range v {
+			is[k] = C.int(nz.Index)
+			es[k] = nz.Value
+			k++
 		}
-		s.allocs = append(s.allocs, rObj)
 	}
+	ss[len(vecs)] = C.int(k)
+	return starts, indices, elements, nil
+}
+
+// cBounds converts a list of bounds to C vectors of lower and upper bounds.
+// It returns nil pointers if bs is nil.  Otherwise, the caller must free both
+// vectors with cFree.
+func cBounds(bs []Bounds) (lower, upper unsafe.Pointer) {
+	if bs == nil {
+		return nil, nil
+	}
+	lower = cMalloc(len(bs)+1, C.double(0.0))
+	upper = cMalloc(len(bs)+1, C.double(0.0))
+	ls := cDoubleSlice(lower, len(bs))
+	us := cDoubleSlice(upper, len(bs))
+	for i, b := range bs {
+		ls[i] = b.Lower
+		us[i] = b.Upper
+	}
+	return lower, upper
+}
+
+// cIndices converts a list of row or column numbers to a C vector.  It
+// returns an error if any index lies outside [0, lim).  On success, the
+// caller must free the vector with cFree.
+func cIndices(idxs []int, lim int) (unsafe.Pointer, error) {
+	cs := cMalloc(len(idxs)+1, C.int(0))
+	is := cIntSlice(cs, len(idxs))
+	for i, idx := range idxs {
+		if idx < 0 || idx >= lim {
+			cFree(cs)
+			return nil, fmt.Errorf("index %d is not in [0, %d)", idx, lim)
+		}
+		is[i] = C.int(idx)
+	}
+	return cs, nil
+}
+
+// AddRows appends rows to a loaded model.  Each row is given as a list of
+// column coefficients.  The row bounds can be nil, in which case they default
+// to {−∞, +∞}.  The existing basis is retained, so a subsequent call to Dual
+// warm-starts from it; this is the usual way to add cuts.  AddRows returns an
+// error if the arguments are inconsistent with each other or with the model.
+func (s *Simplex) AddRows(rb []Bounds, rows [][]Nonzero) error {
+	if rb != nil && len(rb) != len(rows) {
+		return fmt.Errorf("%w: Simplex.AddRows incorrect number of row bounds %d vs %d", ErrDimensionMismatch, len(rb), len(rows))
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	_, nc := s.Dims()
+	starts, cols, elts, err := cSparseVectors(rows, nc)
+	if err != nil {
+		return fmt.Errorf("%w: Simplex.AddRows column %v", ErrIndexOutOfRange, err)
+	}
+	defer cFree(starts)
+	defer cFree(cols)
+	defer cFree(elts)
+	rowLB, rowUB := cBounds(rb)
+	defer cFree(rowLB)
+	defer cFree(rowUB)
+	var cErr C.clp_error
+	C.simplex_add_rows(s.model, C.int(len(rows)),
+		(*C.double)(rowLB), (*C.double)(rowUB),
+		(*C.int)(starts), (*C.int)(cols), (*C.double)(elts), &cErr)
+	return cError("Simplex.AddRows", &cErr)
+}
 
-	// With all of our parameters ready, we can call our C wrapper function.
-	C.simplex_load_problem(s.model, matrix.matrix,
+// AddColumns appends columns to a loaded model.  Each column is given as a
+// list of row coefficients.  The column bounds and objective-function
+// coefficients can be nil, in which case they default to {0, ∞} and 0,
+// respectively.  The existing basis is retained, so a subsequent call to
+// Primal warm-starts from it; this is the usual way to add columns during
+// column generation.  AddColumns returns an error if the arguments are
+// inconsistent with each other or with the model.
+func (s *Simplex) AddColumns(cb []Bounds, obj []float64, cols [][]Nonzero) error {
+	if cb != nil && len(cb) != len(cols) {
+		return fmt.Errorf("%w: Simplex.AddColumns incorrect number of column bounds %d vs %d", ErrDimensionMismatch, len(cb), len(cols))
+	}
+	if obj != nil && len(obj) != len(cols) {
+		return fmt.Errorf("%w: Simplex.AddColumns incorrect length of objective function %d vs %d", ErrDimensionMismatch, len(obj), len(cols))
+	}
+	if len(cols) == 0 {
+		return nil
+	}
+	nr, _ := s.Dims()
+	starts, rows, elts, err := cSparseVectors(cols, nr)
+	if err != nil {
+		return fmt.Errorf("%w: Simplex.AddColumns row %v", ErrIndexOutOfRange, err)
+	}
+	defer cFree(starts)
+	defer cFree(rows)
+	defer cFree(elts)
+	colLB, colUB := cBounds(cb)
+	defer cFree(colLB)
+	defer cFree(colUB)
+	cObj := cDoubles(obj)
+	defer cFree(cObj)
+	var cErr C.clp_error
+	C.simplex_add_cols(s.model, C.int(len(cols)),
 		(*C.double)(colLB), (*C.double)(colUB), (*C.double)(cObj),
-		(*C.double)(rowLB), (*C.double)(rowUB), (*C.double)(rObj))
+		(*C.int)(starts), (*C.int)(rows), (*C.double)(elts), &cErr)
+	return cError("Simplex.AddColumns", &cErr)
+}
+
+// DeleteRows removes a list of rows from a loaded model.  The basis status of
+// the remaining rows and columns is retained.  DeleteRows returns an error if
+// any row number is out of range.
+func (s *Simplex) DeleteRows(rows []int) error {
+	nr, _ := s.Dims()
+	cRows, err := cIndices(rows, nr)
+	if err != nil {
+		return fmt.Errorf("%w: Simplex.DeleteRows row %v", ErrIndexOutOfRange, err)
+	}
+	defer cFree(cRows)
+	var cErr C.clp_error
+	C.simplex_delete_rows(s.model, C.int(len(rows)), (*C.int)(cRows), &cErr)
+	return cError("Simplex.DeleteRows", &cErr)
+}
+
+// DeleteColumns removes a list of columns from a loaded model.  The basis
+// status of the remaining rows and columns is retained.  DeleteColumns
+// returns an error if any column number is out of range.
+func (s *Simplex) DeleteColumns(cols []int) error {
+	_, nc := s.Dims()
+	cCols, err := cIndices(cols, nc)
+	if err != nil {
+		return fmt.Errorf("%w: Simplex.DeleteColumns column %v", ErrIndexOutOfRange, err)
+	}
+	defer cFree(cCols)
+	var cErr C.clp_error
+	C.simplex_delete_cols(s.model, C.int(len(cols)), (*C.int)(cCols), &cErr)
+	return cError("Simplex.DeleteColumns", &cErr)
+}
+
+// checkRow returns an error wrapping ErrIndexOutOfRange if row is not a row
+// of the model.  what names the method for the message.
+func (s *Simplex) checkRow(what string, row int) error {
+	if nr, _ := s.Dims(); row < 0 || row >= nr {
+		return fmt.Errorf("%w: %s row %d is not in [0, %d)", ErrIndexOutOfRange, what, row, nr)
+	}
+	return nil
+}
+
+// checkColumn returns an error wrapping ErrIndexOutOfRange if col is not a
+// column of the model.  what names the method for the message.
+func (s *Simplex) checkColumn(what string, col int) error {
+	if _, nc := s.Dims(); col < 0 || col >= nc {
+		return fmt.Errorf("%w: %s column %d is not in [0, %d)", ErrIndexOutOfRange, what, col, nc)
+	}
+	return nil
+}
+
+// cBoundPairs converts a list of bounds to a single C vector that alternates
+// lower and upper bounds.  The caller must free the result with cFree.
+func cBoundPairs(bs []Bounds) unsafe.Pointer {
+	pairs := cMalloc(2*len(bs)+1, C.double(0.0))
+	ps := cDoubleSlice(pairs, 2*len(bs))
+	for i, b := range bs {
+		ps[2*i] = b.Lower
+		ps[2*i+1] = b.Upper
+	}
+	return pairs
+}
+
+// cDoubles converts a list of Go float64s to a C vector.  It returns a nil
+// pointer if vs is nil.  Otherwise, the caller must free the result with
+// cFree.
+func cDoubles(vs []float64) unsafe.Pointer {
+	if vs == nil {
+		return nil
+	}
+	cs := cMalloc(len(vs)+1, C.double(0.0))
+	copy(cDoubleSlice(cs, len(vs)), vs)
+	return cs
+}
+
+// SetColumnBounds changes the lower and upper bound on a single column of a
+// loaded model.  Unlike reloading the problem, this lets a subsequent solve
+// warm-start from the current basis.  SetColumnBounds returns an error if the
+// column number is out of range.
+func (s *Simplex) SetColumnBounds(col int, b Bounds) error {
+	if err := s.checkColumn("Simplex.SetColumnBounds", col); err != nil {
+		return err
+	}
+	var cErr C.clp_error
+	C.simplex_set_col_bounds(s.model, C.int(col), C.double(b.Lower), C.double(b.Upper), &cErr)
+	return cError("Simplex.SetColumnBounds", &cErr)
+}
+
+// SetRowBounds changes the lower and upper bound on a single row of a loaded
+// model.  SetRowBounds returns an error if the row number is out of range.
+func (s *Simplex) SetRowBounds(row int, b Bounds) error {
+	if err := s.checkRow("Simplex.SetRowBounds", row); err != nil {
+		return err
+	}
+	var cErr C.clp_error
+	C.simplex_set_row_bounds(s.model, C.int(row), C.double(b.Lower), C.double(b.Upper), &cErr)
+	return cError("Simplex.SetRowBounds", &cErr)
+}
+
+// SetColumnSetBounds changes the bounds on a list of columns of a loaded
+// model.  It returns an error if the number of columns and bounds differ or
+// if any column number is out of range.
+func (s *Simplex) SetColumnSetBounds(cols []int, bs []Bounds) error {
+	if len(cols) != len(bs) {
+		return fmt.Errorf("%w: Simplex.SetColumnSetBounds given %d bounds for %d columns", ErrDimensionMismatch, len(bs), len(cols))
+	}
+	_, nc := s.Dims()
+	cCols, err := cIndices(cols, nc)
+	if err != nil {
+		return fmt.Errorf("%w: Simplex.SetColumnSetBounds column %v", ErrIndexOutOfRange, err)
+	}
+	defer cFree(cCols)
+	pairs := cBoundPairs(bs)
+	defer cFree(pairs)
+	var cErr C.clp_error
+	C.simplex_set_col_set_bounds(s.model, C.int(len(cols)), (*C.int)(cCols), (*C.double)(pairs), &cErr)
+	return cError("Simplex.SetColumnSetBounds", &cErr)
+}
+
+// SetRowSetBounds changes the bounds on a list of rows of a loaded model.  It
+// returns an error if the number of rows and bounds differ or if any row
+// number is out of range.
+func (s *Simplex) SetRowSetBounds(rows []int, bs []Bounds) error {
+	if len(rows) != len(bs) {
+		return fmt.Errorf("%w: Simplex.SetRowSetBounds given %d bounds for %d rows", ErrDimensionMismatch, len(bs), len(rows))
+	}
+	nr, _ := s.Dims()
+	cRows, err := cIndices(rows, nr)
+	if err != nil {
+		return fmt.Errorf("%w: Simplex.SetRowSetBounds row %v", ErrIndexOutOfRange, err)
+	}
+	defer cFree(cRows)
+	pairs := cBoundPairs(bs)
+	defer cFree(pairs)
+	var cErr C.clp_error
+	C.simplex_set_row_set_bounds(s.model, C.int(len(rows)), (*C.int)(cRows), (*C.double)(pairs), &cErr)
+	return cError("Simplex.SetRowSetBounds", &cErr)
+}
+
+// SetObjectiveCoefficient changes a single column's coefficient in the
+// objective function of a loaded model.  SetObjectiveCoefficient returns an
+// error if the column number is out of range.
+func (s *Simplex) SetObjectiveCoefficient(col int, v float64) error {
+	if err := s.checkColumn("Simplex.SetObjectiveCoefficient", col); err != nil {
+		return err
+	}
+	cCol := C.int(col)
+	cV := C.double(v)
+	var cErr C.clp_error
+	C.simplex_set_obj_coeffs(s.model, 1, &cCol, &cV, &cErr)
+	return cError("Simplex.SetObjectiveCoefficient", &cErr)
+}
+
+// SetObjectiveCoefficients changes the objective-function coefficients of a
+// list of columns of a loaded model.  It returns an error if the number of
+// columns and coefficients differ or if any column number is out of range.
+func (s *Simplex) SetObjectiveCoefficients(cols []int, vs []float64) error {
+	if len(cols) != len(vs) {
+		return fmt.Errorf("%w: Simplex.SetObjectiveCoefficients given %d coefficients for %d columns", ErrDimensionMismatch, len(vs), len(cols))
+	}
+	_, nc := s.Dims()
+	cCols, err := cIndices(cols, nc)
+	if err != nil {
+		return fmt.Errorf("%w: Simplex.SetObjectiveCoefficients column %v", ErrIndexOutOfRange, err)
+	}
+	defer cFree(cCols)
+	cVs := cDoubles(vs)
+	defer cFree(cVs)
+	var cErr C.clp_error
+	C.simplex_set_obj_coeffs(s.model, C.int(len(cols)), (*C.int)(cCols), (*C.double)(cVs), &cErr)
+	return cError("Simplex.SetObjectiveCoefficients", &cErr)
+}
+
+// SetObjective replaces the entire objective function of a loaded model.  It
+// returns an error if the number of coefficients does not match the number of
+// columns.
+func (s *Simplex) SetObjective(obj []float64) error {
+	_, nc := s.Dims()
+	if len(obj) != nc {
+		return fmt.Errorf("%w: Simplex.SetObjective incorrect length of objective function %d vs %d", ErrDimensionMismatch, len(obj), nc)
+	}
+	cObj := cDoubles(obj)
+	defer cFree(cObj)
+	var cErr C.clp_error
+	C.simplex_set_obj_coeffs(s.model, C.int(nc), nil, (*C.double)(cObj), &cErr)
+	return cError("Simplex.SetObjective", &cErr)
+}
+
+// checkElement returns an error wrapping ErrIndexOutOfRange if (row, col)
+// does not lie within the constraint matrix.  The message names whichever of
+// the row and column does exist.  what names the method for the message.
+func (s *Simplex) checkElement(what string, row, col int) error {
+	nr, nc := s.Dims()
+	rowOK := row >= 0 && row < nr
+	colOK := col >= 0 && col < nc
+	switch {
+	case rowOK && colOK:
+		return nil
+	case rowOK:
+		return fmt.Errorf("%w: %s given %s and column %d, which is not in [0, %d)",
+			ErrIndexOutOfRange, what, s.rowLabel(row), col, nc)
+	case colOK:
+		return fmt.Errorf("%w: %s given row %d, which is not in [0, %d), and %s",
+			ErrIndexOutOfRange, what, row, nr, s.columnLabel(col))
+	default:
+		return fmt.Errorf("%w: %s given row %d and column %d, which are not in [0, %d) and [0, %d)",
+			ErrIndexOutOfRange, what, row, col, nr, nc)
+	}
+}
+
+// ModifyCoefficient changes a single element of a loaded model's constraint
+// matrix, inserting the element if it was previously zero.  The basis is
+// retained, but the solver must rebuild its internal copy of the matrix on
+// the next solve.  ModifyCoefficient returns an error if the row or column
+// number is out of range.
+func (s *Simplex) ModifyCoefficient(row, col int, v float64) error {
+	if err := s.checkElement("Simplex.ModifyCoefficient", row, col); err != nil {
+		return err
+	}
+	cRow := C.int(row)
+	cCol := C.int(col)
+	cV := C.double(v)
+	var cErr C.clp_error
+	C.simplex_modify_coeffs(s.model, 1, &cRow, &cCol, &cV, &cErr)
+	return cError("Simplex.ModifyCoefficient", &cErr)
+}
+
+// ModifyCoefficients changes a list of elements of a loaded model's
+// constraint matrix, where element i lies at row rows[i] and column cols[i].
+// It returns an error if the lists' lengths differ or if any row or column
+// number is out of range.
+func (s *Simplex) ModifyCoefficients(rows, cols []int, vs []float64) error {
+	if len(rows) != len(cols) || len(rows) != len(vs) {
+		return fmt.Errorf("%w: Simplex.ModifyCoefficients given %d rows, %d columns, and %d values", ErrDimensionMismatch, len(rows), len(cols), len(vs))
+	}
+	for i := range rows {
+		if err := s.checkElement("Simplex.ModifyCoefficients", rows[i], cols[i]); err != nil {
+			return err
+		}
+	}
+	nr, nc := s.Dims()
+	cRows, _ := cIndices(rows, nr) // Already checked, so cIndices cannot fail.
+	defer cFree(cRows)
+	cCols, _ := cIndices(cols, nc)
+	defer cFree(cCols)
+	cVs := cDoubles(vs)
+	defer cFree(cVs)
+	var cErr C.clp_error
+	C.simplex_modify_coeffs(s.model, C.int(len(vs)), (*C.int)(cRows), (*C.int)(cCols), (*C.double)(cVs), &cErr)
+	return cError("Simplex.ModifyCoefficients", &cErr)
 }
 
 // An OptDirection specifies the direction of optimization (maximize, minimize,
@@ -148,43 +675,117 @@ const (
 // SetOptimizationDirection specifies whether the objective function should be
 // minimized, maximized, or ignored.
 func (s *Simplex) SetOptimizationDirection(d OptDirection) {
-	C.simplex_set_opt_dir(s.model, C.double(d))
+	var cErr C.clp_error
+	C.simplex_set_opt_dir(s.model, C.double(d), &cErr)
+	s.recordErr(cError("Simplex.SetOptimizationDirection", &cErr))
 }
 
 // SetMaxIterations sets the maximum number of iterations for a solve.
 func (s *Simplex) SetMaxIterations(maxIter int) {
-	C.set_max_iterations(s.model, C.int(maxIter))
+	var cErr C.clp_error
+	C.set_max_iterations(s.model, C.int(maxIter), &cErr)
+	s.recordErr(cError("Simplex.SetMaxIterations", &cErr))
 }
 
 // MaxIterations returns the maximum number of iterations for a solve.
 func (s *Simplex) MaxIterations() int {
-	return int(C.max_iterations(s.model))
+	var cErr C.clp_error
+	v := C.max_iterations(s.model, &cErr)
+	s.recordErr(cError("Simplex.MaxIterations", &cErr))
+	return int(v)
+}
+
+// NumberIterations returns the number of iterations performed by the most
+// recent solve.
+func (s *Simplex) NumberIterations() int {
+	var cErr C.clp_error
+	v := C.number_iterations(s.model, &cErr)
+	s.recordErr(cError("Simplex.NumberIterations", &cErr))
+	return int(v)
 }
 
 // SetMaxSeconds sets the maximum number of seconds for a solve.
 func (s *Simplex) SetMaxSeconds(maxSeconds float64) {
-	C.set_max_seconds(s.model, C.double(maxSeconds))
+	var cErr C.clp_error
+	C.set_max_seconds(s.model, C.double(maxSeconds), &cErr)
+	s.recordErr(cError("Simplex.SetMaxSeconds", &cErr))
 }
 
 // MaxSeconds returns the maximum number of seconds for a solve.
 func (s *Simplex) MaxSeconds() float64 {
-	return float64(C.max_seconds(s.model))
+	var cErr C.clp_error
+	v := C.max_seconds(s.model, &cErr)
+	s.recordErr(cError("Simplex.MaxSeconds", &cErr))
+	return float64(v)
 }
 
-// SecondaryStatus returns the secondary status of a model.
+// SecondaryStatus returns the secondary status of a model.  For
+// compatibility with earlier versions, it returns the status as a
+// SimplexStatus.  Result reports the same value as a SecondaryStatus, which
+// prints as the name of one of the Secondary constants.
 func (s *Simplex) SecondaryStatus() SimplexStatus {
-	return SimplexStatus(C.secondary_status(s.model))
+	return SimplexStatus(s.secondaryStatus())
 }
 
-// WriteMPS writes the model to the named MPS file.  It returns true on success
-// and false on failure.
+// secondaryStatus implements SecondaryStatus but returns a SecondaryStatus.
+func (s *Simplex) secondaryStatus() SecondaryStatus {
+	var cErr C.clp_error
+	v := C.secondary_status(s.model, &cErr)
+	s.recordErr(cError("Simplex.SecondaryStatus", &cErr))
+	return SecondaryStatus(v)
+}
+
+// WriteMPS writes the model to the named MPS file, using the model's row and
+// column names if any were assigned.  It returns true on success and false on
+// failure.  See TryWriteMPS for a variant that returns an error instead.
 func (s *Simplex) WriteMPS(filename string) bool {
+	return s.TryWriteMPS(filename) == nil
+}
+
+// TryWriteMPS is like WriteMPS but returns an error wrapping ErrIO on
+// failure.
+func (s *Simplex) TryWriteMPS(filename string) error {
+	cFilename := C.CString(filename)
+	defer C.free(unsafe.Pointer(cFilename))
+	var cErr C.clp_error
+	st := C.write_mps(s.model, cFilename, &cErr)
+	if err := cError("Simplex.WriteMPS", &cErr); err != nil {
+		return err
+	}
+	if st != 0 {
+		return fmt.Errorf("%w: failed to write MPS file %s", ErrIO, filename)
+	}
+	return nil
+}
+
+// ReadMPS replaces the model with one read from the named MPS file.  Both
+// fixed- and free-format MPS are accepted, and the file may be gzipped if the
+// underlying CoinUtils library was built with zlib support.  Column bounds,
+// row bounds and ranges, the objective function and its constant term, and
+// row and column names are all loaded into the model.  ReadMPS returns an
+// error wrapping ErrIO if the file cannot be opened or one wrapping
+// ErrInvalidArgument if its contents are malformed.
+func (s *Simplex) ReadMPS(filename string) error {
 	cFilename := C.CString(filename)
 	defer C.free(unsafe.Pointer(cFilename))
-	return C.write_mps(s.model, cFilename) == 0
+	var cErr C.clp_error
+	st := int(C.read_mps(s.model, cFilename, 1, 0, &cErr))
+	if err := cError("Simplex.ReadMPS", &cErr); err != nil {
+		return err
+	}
+	switch {
+	case st < 0:
+		return fmt.Errorf("%w: failed to open MPS file %s", ErrIO, filename)
+	case st > 0:
+		return fmt.Errorf("%w: encountered %d error(s) parsing MPS file %s", ErrInvalidArgument, st, filename)
+	}
+	s.matrix = nil
+	return nil
 }
 
-// A ValuesPass specifies whether to perform a values pass.
+// A ValuesPass specifies whether to perform a values pass.  The values from
+// which a values pass starts can be supplied with SetPrimalColumnSolution and
+// related methods.
 type ValuesPass int
 
 // These constants specify the sort of value pass to perform.
@@ -211,51 +812,173 @@ type SimplexStatus int
 
 // These constants are the possible values for a SimplexStatus.
 const (
-	Optimal    SimplexStatus = 0
-	Infeasible               = 1
-	Unbounded                = 2
+	Optimal               SimplexStatus = 0
+	Infeasible                          = 1
+	Unbounded                           = 2
+	StoppedOnLimits                     = 3
+	StoppedOnErrors                     = 4
+	StoppedByEventHandler               = 5
+	NotSolved                           = 6 // No solve has been performed; never returned by CLP
 )
 
-// These constants are the corresponding values for the secondary status
+// String returns the name of a SimplexStatus.
+func (ss SimplexStatus) String() string {
+	switch ss {
+	case Optimal:
+		return "Optimal"
+	case Infeasible:
+		return "Infeasible"
+	case Unbounded:
+		return "Unbounded"
+	case StoppedOnLimits:
+		return "StoppedOnLimits"
+	case StoppedOnErrors:
+		return "StoppedOnErrors"
+	case StoppedByEventHandler:
+		return "StoppedByEventHandler"
+	case NotSolved:
+		return "NotSolved"
+	default:
+		return fmt.Sprintf("SimplexStatus(%d)", int(ss))
+	}
+}
+
+// A SecondaryStatus provides additional detail about the outcome of a
+// simplex optimization.
+type SecondaryStatus int
+
+// These constants are the possible values for a SecondaryStatus.  They are
+// untyped so that they can be compared both with a SecondaryStatus and with
+// the SimplexStatus returned by Simplex.SecondaryStatus.
 const (
-	SecondaryNone                                  SimplexStatus = 0
-	SecondaryPrimalInfeasible                                    = 1
-	SecondaryScaledOptimalUnscaledPrimalInfeasible               = 2
-	SecondaryScaledOptimalUnscaledDualInfeasible                 = 3
-	SecondaryScaledOptimalUnscaledBothInfeasible                 = 4
-	SecondaryGaveUp                                              = 5
-	SecondaryFailedEmptyCheck                                    = 6
-	SecondaryPostSolveNotOptimal                                 = 7
-	SecondaryFailedBadElement                                    = 8
-	SecondaryStoppedOnTime                                       = 9
-	SecondaryStoppedPrimalInfeasible                             = 10
+	SecondaryNone                                  = 0
+	SecondaryPrimalInfeasible                      = 1
+	SecondaryScaledOptimalUnscaledPrimalInfeasible = 2
+	SecondaryScaledOptimalUnscaledDualInfeasible   = 3
+	SecondaryScaledOptimalUnscaledBothInfeasible   = 4
+	SecondaryGaveUp                                = 5
+	SecondaryFailedEmptyCheck                      = 6
+	SecondaryPostSolveNotOptimal                   = 7
+	SecondaryFailedBadElement                      = 8
+	SecondaryStoppedOnTime                         = 9
+	SecondaryStoppedPrimalInfeasible               = 10
+	SecondaryStoppedByUser                         = 100
 )
 
-// Primal solves a simplex model with the primal method.
+// String returns the name of a SecondaryStatus.
+func (ss SecondaryStatus) String() string {
+	switch ss {
+	case SecondaryNone:
+		return "SecondaryNone"
+	case SecondaryPrimalInfeasible:
+		return "SecondaryPrimalInfeasible"
+	case SecondaryScaledOptimalUnscaledPrimalInfeasible:
+		return "SecondaryScaledOptimalUnscaledPrimalInfeasible"
+	case SecondaryScaledOptimalUnscaledDualInfeasible:
+		return "SecondaryScaledOptimalUnscaledDualInfeasible"
+	case SecondaryScaledOptimalUnscaledBothInfeasible:
+		return "SecondaryScaledOptimalUnscaledBothInfeasible"
+	case SecondaryGaveUp:
+		return "SecondaryGaveUp"
+	case SecondaryFailedEmptyCheck:
+		return "SecondaryFailedEmptyCheck"
+	case SecondaryPostSolveNotOptimal:
+		return "SecondaryPostSolveNotOptimal"
+	case SecondaryFailedBadElement:
+		return "SecondaryFailedBadElement"
+	case SecondaryStoppedOnTime:
+		return "SecondaryStoppedOnTime"
+	case SecondaryStoppedPrimalInfeasible:
+		return "SecondaryStoppedPrimalInfeasible"
+	case SecondaryStoppedByUser:
+		return "SecondaryStoppedByUser"
+	default:
+		return fmt.Sprintf("SecondaryStatus(%d)", int(ss))
+	}
+}
+
+// Primal solves a simplex model with the primal method.  If CLP throws an
+// exception, Primal returns StoppedOnErrors, and Err reports the exception.
+// Result provides further details about the solve, and PrimalResult returns
+// them directly.
 func (s *Simplex) Primal(vp ValuesPass, sfo StartFinishOptions) SimplexStatus {
-	return SimplexStatus(C.simplex_primal(s.model, C.int(vp), C.int(sfo)))
+	st, err := s.primal(vp, sfo)
+	s.recordErr(err)
+	return st
 }
 
-// Dual solves a simplex model with the dual method.
+// primal implements Primal.  It returns StoppedOnErrors and an error if CLP
+// throws an exception.
+func (s *Simplex) primal(vp ValuesPass, sfo StartFinishOptions) (SimplexStatus, error) {
+	var cErr C.clp_error
+	start := time.Now()
+	st := C.simplex_primal(s.model, C.int(vp), C.int(sfo), &cErr)
+	return s.solveStatus("Simplex.Primal", start, st, &cErr)
+}
+
+// Dual solves a simplex model with the dual method.  If CLP throws an
+// exception, Dual returns StoppedOnErrors, and Err reports the exception.
+// Result provides further details about the solve, and DualResult returns
+// them directly.
 func (s *Simplex) Dual(vp ValuesPass, sfo StartFinishOptions) SimplexStatus {
-	return SimplexStatus(C.simplex_dual(s.model, C.int(vp), C.int(sfo)))
+	st, err := s.dual(vp, sfo)
+	s.recordErr(err)
+	return st
+}
+
+// dual implements Dual.  It returns StoppedOnErrors and an error if CLP
+// throws an exception.
+func (s *Simplex) dual(vp ValuesPass, sfo StartFinishOptions) (SimplexStatus, error) {
+	var cErr C.clp_error
+	start := time.Now()
+	st := C.simplex_dual(s.model, C.int(vp), C.int(sfo), &cErr)
+	return s.solveStatus("Simplex.Dual", start, st, &cErr)
 }
 
 // Barrier solves a simplex model with the barrier method.  The argument says
-// whether to cross over to simplex.
+// whether to cross over to simplex.  If CLP throws an exception (for
+// example, because it runs out of memory while factorizing), Barrier returns
+// StoppedOnErrors, and Err reports the exception.  Result provides further
+// details about the solve, and BarrierResult returns them directly.
 func (s *Simplex) Barrier(xover bool) SimplexStatus {
+	st, err := s.barrier(xover)
+	s.recordErr(err)
+	return st
+}
+
+// barrier implements Barrier.  It returns StoppedOnErrors and an error if CLP
+// throws an exception.
+func (s *Simplex) barrier(xover bool) (SimplexStatus, error) {
 	var b C.int
 	if xover {
 		b = 1
 	}
-	return SimplexStatus(C.simplex_barrier(s.model, b))
+	var cErr C.clp_error
+	start := time.Now()
+	st := C.simplex_barrier(s.model, b, &cErr)
+	return s.solveStatus("Simplex.Barrier", start, st, &cErr)
+}
+
+// solveStatus converts the status returned by a solve to a SimplexStatus,
+// substituting StoppedOnErrors if the solve threw an exception.  It records
+// the status and the time elapsed since start for Result to report.
+func (s *Simplex) solveStatus(what string, start time.Time, st C.int, cErr *C.clp_error) (SimplexStatus, error) {
+	s.solveTime = time.Since(start)
+	s.status = SimplexStatus(st)
+	s.solved = true
+	err := cError(what, cErr)
+	if err != nil {
+		s.status = StoppedOnErrors
+	}
+	return s.status, err
 }
 
 // PrimalTolerance returns the tolerance currently associated with the
 // variables in a simplex model.
 func (s *Simplex) PrimalTolerance() float64 {
-	var tolerance C.double
-	tolerance = C.simplex_primal_get_tolerance(s.model)
+	var cErr C.clp_error
+	tolerance := C.simplex_primal_get_tolerance(s.model, &cErr)
+	s.recordErr(cError("Simplex.PrimalTolerance", &cErr))
 	return float64(tolerance)
 }
 
@@ -264,24 +987,41 @@ func (s *Simplex) PrimalTolerance() float64 {
 // it is less than the tolerance…below its lower bound and less than it above
 // its upper bound".
 func (s *Simplex) SetPrimalTolerance(tolerance float64) {
-	C.simplex_primal_set_tolerance(s.model, C.double(tolerance))
+	var cErr C.clp_error
+	C.simplex_primal_set_tolerance(s.model, C.double(tolerance), &cErr)
+	s.recordErr(cError("Simplex.SetPrimalTolerance", &cErr))
 }
 
 // ReducedGradient solves a simplex model with the reduced-gradient method.
 // The argument says whether to get a feasible solution (false) or to use a
-// solution.
+// solution.  If CLP throws an exception, ReducedGradient returns
+// StoppedOnErrors, and Err reports the exception.  Result provides further
+// details about the solve, and ReducedGradientResult returns them directly.
 func (s *Simplex) ReducedGradient(phase bool) SimplexStatus {
+	st, err := s.reducedGradient(phase)
+	s.recordErr(err)
+	return st
+}
+
+// reducedGradient implements ReducedGradient.  It returns StoppedOnErrors and
+// an error if CLP throws an exception.
+func (s *Simplex) reducedGradient(phase bool) (SimplexStatus, error) {
 	var b C.int
 	if phase {
 		b = 1
 	}
-	return SimplexStatus(C.simplex_red_grad(s.model, b))
+	var cErr C.clp_error
+	start := time.Now()
+	st := C.simplex_red_grad(s.model, b, &cErr)
+	return s.solveStatus("Simplex.ReducedGradient", start, st, &cErr)
 }
 
 // Dims returns a model's dimensions (rows and columns).
 func (s *Simplex) Dims() (rows, cols int) {
 	var r, c C.int
-	C.simplex_get_dims(s.model, &r, &c)
+	var cErr C.clp_error
+	C.simplex_get_dims(s.model, &r, &c, &cErr)
+	s.recordErr(cError("Simplex.Dims", &cErr))
 	rows = int(r)
 	cols = int(c)
 	return
@@ -301,61 +1041,485 @@ const (
 
 // SetScaling determines how the problem data are to be scaled.
 func (s *Simplex) SetScaling(sc Scaling) {
-	C.simplex_scaling(s.model, C.int(sc))
+	var cErr C.clp_error
+	C.simplex_scaling(s.model, C.int(sc), &cErr)
+	s.recordErr(cError("Simplex.SetScaling", &cErr))
 }
 
 // PrimalColumnSolution returns the primal column solution computed by a solver.
 func (s *Simplex) PrimalColumnSolution() []float64 {
-	_, nc := s.Dims()
-	soln := make([]float64, nc)
-	cSoln := C.simplex_get_prim_col_soln(s.model)
-	for i := range soln {
-		soln[i] = cGetArrayDouble(unsafe.Pointer(cSoln), i)
+	return s.PrimalColumnSolutionInto(nil)
+}
+
+// PrimalColumnSolutionInto is like PrimalColumnSolution but stores the solution in dst,
+// reusing dst's storage if its capacity suffices.  It returns dst resliced,
+// or reallocated, to the number of columns.  Calling PrimalColumnSolutionInto
+// repeatedly with the same buffer avoids allocating on every call.
+func (s *Simplex) PrimalColumnSolutionInto(dst []float64) []float64 {
+	_, n := s.Dims()
+	var cErr C.clp_error
+	cSoln := C.simplex_get_prim_col_soln(s.model, &cErr)
+	if s.recordErr(cError("Simplex.PrimalColumnSolution", &cErr)) {
+		return nil
 	}
-	runtime.KeepAlive(s.matrix)
-	return soln
+	dst = sizedFloats(dst, n)
+	copy(dst, cDoubleSlice(unsafe.Pointer(cSoln), n))
+	runtime.KeepAlive(s)
+	return dst
 }
 
 // DualColumnSolution returns the dual column solution computed by a solver.
 func (s *Simplex) DualColumnSolution() []float64 {
-	_, nc := s.Dims()
-	soln := make([]float64, nc)
-	cSoln := C.simplex_get_dual_col_soln(s.model)
-	for i := range soln {
-		soln[i] = cGetArrayDouble(unsafe.Pointer(cSoln), i)
+	return s.DualColumnSolutionInto(nil)
+}
+
+// DualColumnSolutionInto is like DualColumnSolution but stores the solution in dst,
+// reusing dst's storage if its capacity suffices.  It returns dst resliced,
+// or reallocated, to the number of columns.  Calling DualColumnSolutionInto
+// repeatedly with the same buffer avoids allocating on every call.
+func (s *Simplex) DualColumnSolutionInto(dst []float64) []float64 {
+	_, n := s.Dims()
+	var cErr C.clp_error
+	cSoln := C.simplex_get_dual_col_soln(s.model, &cErr)
+	if s.recordErr(cError("Simplex.DualColumnSolution", &cErr)) {
+		return nil
 	}
-	runtime.KeepAlive(s.matrix)
-	return soln
+	dst = sizedFloats(dst, n)
+	copy(dst, cDoubleSlice(unsafe.Pointer(cSoln), n))
+	runtime.KeepAlive(s)
+	return dst
 }
 
 // PrimalRowSolution returns the primal row solution computed by a solver.
 func (s *Simplex) PrimalRowSolution() []float64 {
-	nr, _ := s.Dims()
-	soln := make([]float64, nr)
-	cSoln := C.simplex_get_prim_row_soln(s.model)
-	for i := range soln {
-		soln[i] = cGetArrayDouble(unsafe.Pointer(cSoln), i)
+	return s.PrimalRowSolutionInto(nil)
+}
+
+// PrimalRowSolutionInto is like PrimalRowSolution but stores the solution in dst,
+// reusing dst's storage if its capacity suffices.  It returns dst resliced,
+// or reallocated, to the number of rows.  Calling PrimalRowSolutionInto
+// repeatedly with the same buffer avoids allocating on every call.
+func (s *Simplex) PrimalRowSolutionInto(dst []float64) []float64 {
+	n, _ := s.Dims()
+	var cErr C.clp_error
+	cSoln := C.simplex_get_prim_row_soln(s.model, &cErr)
+	if s.recordErr(cError("Simplex.PrimalRowSolution", &cErr)) {
+		return nil
 	}
-	runtime.KeepAlive(s.matrix)
-	return soln
+	dst = sizedFloats(dst, n)
+	copy(dst, cDoubleSlice(unsafe.Pointer(cSoln), n))
+	runtime.KeepAlive(s)
+	return dst
 }
 
 // DualRowSolution returns the dual row solution computed by a solver.
 func (s *Simplex) DualRowSolution() []float64 {
+	return s.DualRowSolutionInto(nil)
+}
+
+// DualRowSolutionInto is like DualRowSolution but stores the solution in dst,
+// reusing dst's storage if its capacity suffices.  It returns dst resliced,
+// or reallocated, to the number of rows.  Calling DualRowSolutionInto
+// repeatedly with the same buffer avoids allocating on every call.
+func (s *Simplex) DualRowSolutionInto(dst []float64) []float64 {
+	n, _ := s.Dims()
+	var cErr C.clp_error
+	cSoln := C.simplex_get_dual_row_soln(s.model, &cErr)
+	if s.recordErr(cError("Simplex.DualRowSolution", &cErr)) {
+		return nil
+	}
+	dst = sizedFloats(dst, n)
+	copy(dst, cDoubleSlice(unsafe.Pointer(cSoln), n))
+	runtime.KeepAlive(s)
+	return dst
+}
+
+// setSolution copies a Go slice into one of a model's writable solution
+// vectors, which must contain n elements.  It returns an error if the slice
+// has the wrong length.
+func (s *Simplex) setSolution(what string, dst *C.double, n int, vals []float64) error {
+	if len(vals) != n {
+		return fmt.Errorf("%w: Simplex.%s given %d values for %d elements", ErrDimensionMismatch, what, len(vals), n)
+	}
+	copy(cDoubleSlice(unsafe.Pointer(dst), n), vals)
+	return nil
+}
+
+// SetPrimalColumnSolution assigns the primal column values from which a
+// subsequent solve with DoValuesPass or OnlyValuesPass will start.  It
+// returns an error if the number of values does not match the number of
+// columns.
+func (s *Simplex) SetPrimalColumnSolution(vals []float64) error {
+	_, nc := s.Dims()
+	var cErr C.clp_error
+	dst := C.simplex_get_prim_col_soln(s.model, &cErr)
+	if err := cError("Simplex.SetPrimalColumnSolution", &cErr); err != nil {
+		return err
+	}
+	return s.setSolution("SetPrimalColumnSolution", dst, nc, vals)
+}
+
+// SetDualColumnSolution assigns the dual column values (reduced costs) from
+// which a subsequent solve will start.  It returns an error if the number of
+// values does not match the number of columns.
+func (s *Simplex) SetDualColumnSolution(vals []float64) error {
+	_, nc := s.Dims()
+	var cErr C.clp_error
+	dst := C.simplex_get_dual_col_soln(s.model, &cErr)
+	if err := cError("Simplex.SetDualColumnSolution", &cErr); err != nil {
+		return err
+	}
+	return s.setSolution("SetDualColumnSolution", dst, nc, vals)
+}
+
+// SetPrimalRowSolution assigns the primal row values (row activities) from
+// which a subsequent solve will start.  It returns an error if the number of
+// values does not match the number of rows.
+func (s *Simplex) SetPrimalRowSolution(vals []float64) error {
 	nr, _ := s.Dims()
-	soln := make([]float64, nr)
-	cSoln := C.simplex_get_dual_row_soln(s.model)
-	for i := range soln {
-		soln[i] = cGetArrayDouble(unsafe.Pointer(cSoln), i)
+	var cErr C.clp_error
+	dst := C.simplex_get_prim_row_soln(s.model, &cErr)
+	if err := cError("Simplex.SetPrimalRowSolution", &cErr); err != nil {
+		return err
 	}
-	runtime.KeepAlive(s.matrix)
-	return soln
+	return s.setSolution("SetPrimalRowSolution", dst, nr, vals)
+}
+
+// SetDualRowSolution assigns the dual row values from which a subsequent
+// solve will start.  It returns an error if the number of values does not
+// match the number of rows.
+func (s *Simplex) SetDualRowSolution(vals []float64) error {
+	nr, _ := s.Dims()
+	var cErr C.clp_error
+	dst := C.simplex_get_dual_row_soln(s.model, &cErr)
+	if err := cError("Simplex.SetDualRowSolution", &cErr); err != nil {
+		return err
+	}
+	return s.setSolution("SetDualRowSolution", dst, nr, vals)
 }
 
 // ObjectiveValue returns the value of the objective function after
 // optimization.
 func (s *Simplex) ObjectiveValue() float64 {
-	return float64(C.simplex_obj_val(s.model))
+	var cErr C.clp_error
+	v := C.simplex_obj_val(s.model, &cErr)
+	s.recordErr(cError("Simplex.ObjectiveValue", &cErr))
+	return float64(v)
+}
+
+// fromCLPInfinity maps CLP's representation of an infinite bound, ±DBL_MAX, to
+// Go's, ±Inf.
+func fromCLPInfinity(v float64) float64 {
+	switch {
+	case v >= math.MaxFloat64:
+		return math.Inf(1)
+	case v <= -math.MaxFloat64:
+		return math.Inf(-1)
+	default:
+		return v
+	}
+}
+
+// Objective returns a copy of the objective-function coefficients of the
+// loaded model.
+func (s *Simplex) Objective() []float64 {
+	_, nc := s.Dims()
+	obj := make([]float64, nc)
+	var cErr C.clp_error
+	cObj := C.simplex_get_obj(s.model, &cErr)
+	if s.recordErr(cError("Simplex.Objective", &cErr)) {
+		return nil
+	}
+	copy(obj, cDoubleSlice(unsafe.Pointer(cObj), nc))
+	return obj
+}
+
+// ColumnBounds returns a copy of the column bounds of the loaded model.
+// Infinite bounds are reported as math.Inf(-1) and math.Inf(1).
+func (s *Simplex) ColumnBounds() []Bounds {
+	_, nc := s.Dims()
+	cb := make([]Bounds, nc)
+	var cErr C.clp_error
+	cLower := C.simplex_get_col_lower(s.model, &cErr)
+	if s.recordErr(cError("Simplex.ColumnBounds", &cErr)) {
+		return nil
+	}
+	cUpper := C.simplex_get_col_upper(s.model, &cErr)
+	if s.recordErr(cError("Simplex.ColumnBounds", &cErr)) {
+		return nil
+	}
+	lower := cDoubleSlice(unsafe.Pointer(cLower), nc)
+	upper := cDoubleSlice(unsafe.Pointer(cUpper), nc)
+	for i := range cb {
+		cb[i].Lower = fromCLPInfinity(lower[i])
+		cb[i].Upper = fromCLPInfinity(upper[i])
+	}
+	return cb
+}
+
+// RowBounds returns a copy of the row bounds of the loaded model.  Infinite
+// bounds are reported as math.Inf(-1) and math.Inf(1).
+func (s *Simplex) RowBounds() []Bounds {
+	nr, _ := s.Dims()
+	rb := make([]Bounds, nr)
+	var cErr C.clp_error
+	cLower := C.simplex_get_row_lower(s.model, &cErr)
+	if s.recordErr(cError("Simplex.RowBounds", &cErr)) {
+		return nil
+	}
+	cUpper := C.simplex_get_row_upper(s.model, &cErr)
+	if s.recordErr(cError("Simplex.RowBounds", &cErr)) {
+		return nil
+	}
+	lower := cDoubleSlice(unsafe.Pointer(cLower), nr)
+	upper := cDoubleSlice(unsafe.Pointer(cUpper), nr)
+	for i := range rb {
+		rb[i].Lower = fromCLPInfinity(lower[i])
+		rb[i].Upper = fromCLPInfinity(upper[i])
+	}
+	return rb
+}
+
+// OptimizationDirection returns whether the objective function is to be
+// minimized, maximized, or ignored.
+func (s *Simplex) OptimizationDirection() OptDirection {
+	var cErr C.clp_error
+	v := C.simplex_get_opt_dir(s.model, &cErr)
+	s.recordErr(cError("Simplex.OptimizationDirection", &cErr))
+	return OptDirection(v)
+}
+
+// ObjectiveOffset returns the constant that CLP subtracts from the objective
+// value.  Note the sign: an objective function of x + 3 has an offset of -3.
+func (s *Simplex) ObjectiveOffset() float64 {
+	var cErr C.clp_error
+	v := C.simplex_get_obj_offset(s.model, &cErr)
+	s.recordErr(cError("Simplex.ObjectiveOffset", &cErr))
+	return float64(v)
+}
+
+// SetObjectiveOffset sets the constant that CLP subtracts from the objective
+// value.
+func (s *Simplex) SetObjectiveOffset(offset float64) {
+	var cErr C.clp_error
+	C.simplex_set_obj_offset(s.model, C.double(offset), &cErr)
+	s.recordErr(cError("Simplex.SetObjectiveOffset", &cErr))
+}
+
+// Matrix returns a copy of the constraint matrix of the loaded model.
+// Modifying the copy does not affect the model.  If CLP throws an exception
+// while copying the matrix, Matrix returns an empty matrix, and Err reports
+// the exception.
+func (s *Simplex) Matrix() *PackedMatrix {
+	var cErr C.clp_error
+	matrix := C.simplex_get_matrix(s.model, &cErr)
+	if s.recordErr(cError("Simplex.Matrix", &cErr)) {
+		return NewPackedMatrix()
+	}
+	return wrapPackedMatrix(matrix)
+}
+
+// RowName returns the name of a row.  CLP makes up a name for rows that were
+// not explicitly assigned one.  RowName returns an error wrapping
+// ErrIndexOutOfRange if row is not a row of the model.
+func (s *Simplex) RowName(row int) (string, error) {
+	if err := s.checkRow("Simplex.RowName", row); err != nil {
+		return "", err
+	}
+	var cErr C.clp_error
+	cName := C.simplex_get_row_name(s.model, C.int(row), &cErr)
+	if err := cError("Simplex.RowName", &cErr); err != nil {
+		return "", err
+	}
+	defer C.free(unsafe.Pointer(cName))
+	return C.GoString(cName), nil
+}
+
+// ColumnName returns the name of a column.  CLP makes up a name for columns
+// that were not explicitly assigned one.  ColumnName returns an error
+// wrapping ErrIndexOutOfRange if col is not a column of the model.
+func (s *Simplex) ColumnName(col int) (string, error) {
+	if err := s.checkColumn("Simplex.ColumnName", col); err != nil {
+		return "", err
+	}
+	var cErr C.clp_error
+	cName := C.simplex_get_col_name(s.model, C.int(col), &cErr)
+	if err := cError("Simplex.ColumnName", &cErr); err != nil {
+		return "", err
+	}
+	defer C.free(unsafe.Pointer(cName))
+	return C.GoString(cName), nil
+}
+
+// rowLabel describes a row for an error message: its number followed by its
+// name if it is a row of the model and was assigned a name.
+func (s *Simplex) rowLabel(row int) string {
+	name, err := s.RowName(row)
+	if err == nil && name != "" && name != fmt.Sprintf("R%07d", row) {
+		return fmt.Sprintf("row %d (%s)", row, name)
+	}
+	return fmt.Sprintf("row %d", row)
+}
+
+// columnLabel describes a column for an error message: its number followed by
+// its name if it is a column of the model and was assigned a name.
+func (s *Simplex) columnLabel(col int) string {
+	name, err := s.ColumnName(col)
+	if err == nil && name != "" && name != fmt.Sprintf("C%07d", col) {
+		return fmt.Sprintf("column %d (%s)", col, name)
+	}
+	return fmt.Sprintf("column %d", col)
+}
+
+// SetRowName assigns a name to a row.  It returns an error wrapping
+// ErrIndexOutOfRange if row is not a row of the model.
+func (s *Simplex) SetRowName(row int, name string) error {
+	if err := s.checkRow("Simplex.SetRowName", row); err != nil {
+		return err
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	var cErr C.clp_error
+	C.simplex_set_row_name(s.model, C.int(row), cName, &cErr)
+	return cError("Simplex.SetRowName", &cErr)
+}
+
+// SetColumnName assigns a name to a column.  It returns an error wrapping
+// ErrIndexOutOfRange if col is not a column of the model.
+func (s *Simplex) SetColumnName(col int, name string) error {
+	if err := s.checkColumn("Simplex.SetColumnName", col); err != nil {
+		return err
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	var cErr C.clp_error
+	C.simplex_set_col_name(s.model, C.int(col), cName, &cErr)
+	return cError("Simplex.SetColumnName", &cErr)
+}
+
+// RowNames returns the names of all rows in order.  If CLP throws an
+// exception, the remaining names are empty, and Err reports the exception.
+func (s *Simplex) RowNames() []string {
+	nr, _ := s.Dims()
+	names := make([]string, nr)
+	for i := range names {
+		var err error
+		names[i], err = s.RowName(i)
+		if s.recordErr(err) {
+			break
+		}
+	}
+	return names
+}
+
+// ColumnNames returns the names of all columns in order.  If CLP throws an
+// exception, the remaining names are empty, and Err reports the exception.
+func (s *Simplex) ColumnNames() []string {
+	_, nc := s.Dims()
+	names := make([]string, nc)
+	for i := range names {
+		var err error
+		names[i], err = s.ColumnName(i)
+		if s.recordErr(err) {
+			break
+		}
+	}
+	return names
+}
+
+// SetRowNames assigns names to all rows.  It returns an error if the number
+// of names does not match the number of rows.
+func (s *Simplex) SetRowNames(names []string) error {
+	nr, _ := s.Dims()
+	if len(names) != nr {
+		return fmt.Errorf("%w: Simplex.SetRowNames given %d names for %d rows", ErrDimensionMismatch, len(names), nr)
+	}
+	if nr == 0 {
+		return nil
+	}
+	cNames := cStrings(names)
+	defer cFreeStrings(cNames, nr)
+	var cErr C.clp_error
+	C.simplex_set_row_names(s.model, 0, C.int(nr), (**C.char)(cNames), &cErr)
+	return cError("Simplex.SetRowNames", &cErr)
+}
+
+// SetColumnNames assigns names to all columns.  It returns an error if the
+// number of names does not match the number of columns.
+func (s *Simplex) SetColumnNames(names []string) error {
+	_, nc := s.Dims()
+	if len(names) != nc {
+		return fmt.Errorf("%w: Simplex.SetColumnNames given %d names for %d columns", ErrDimensionMismatch, len(names), nc)
+	}
+	if nc == 0 {
+		return nil
+	}
+	cNames := cStrings(names)
+	defer cFreeStrings(cNames, nc)
+	var cErr C.clp_error
+	C.simplex_set_col_names(s.model, 0, C.int(nc), (**C.char)(cNames), &cErr)
+	return cError("Simplex.SetColumnNames", &cErr)
+}
+
+// RowNameMap returns a map from each row name to the corresponding row
+// number, for addressing constraints by name.
+func (s *Simplex) RowNameMap() map[string]int {
+	names := s.RowNames()
+	m := make(map[string]int, len(names))
+	for i, n := range names {
+		m[n] = i
+	}
+	return m
+}
+
+// ColumnNameMap returns a map from each column name to the corresponding
+// column number, for addressing variables by name.
+func (s *Simplex) ColumnNameMap() map[string]int {
+	names := s.ColumnNames()
+	m := make(map[string]int, len(names))
+	for i, n := range names {
+		m[n] = i
+	}
+	return m
+}
+
+// SequenceName returns the name of a row or column given its sequence
+// number, as reported by PrimalRanging and DualRanging.  CLP numbers columns
+// first, then rows.  SequenceName returns the empty string for a negative
+// sequence number, which CLP uses to indicate "no variable", and for one
+// past the last row.  If CLP throws an exception, SequenceName returns the
+// empty string, and Err reports the exception.
+func (s *Simplex) SequenceName(seq int) string {
+	nr, nc := s.Dims()
+	var name string
+	var err error
+	switch {
+	case seq < 0 || seq >= nc+nr:
+		return ""
+	case seq < nc:
+		name, err = s.ColumnName(seq)
+	default:
+		name, err = s.RowName(seq - nc)
+	}
+	s.recordErr(err)
+	return name
+}
+
+// isInteger says whether a column is integer-valued.  CLP itself ignores
+// integrality, but it preserves it for the benefit of file formats and
+// branch-and-bound codes.
+func (s *Simplex) isInteger(col int) bool {
+	var cErr C.clp_error
+	isInt := C.simplex_is_integer(s.model, C.int(col), &cErr)
+	s.recordErr(cError("Simplex.isInteger", &cErr))
+	return isInt != 0
+}
+
+// setInteger marks a column as integer-valued.
+func (s *Simplex) setInteger(col int) {
+	var cErr C.clp_error
+	C.simplex_set_integer(s.model, C.int(col), &cErr)
+	s.recordErr(cError("Simplex.setInteger", &cErr))
 }
 
 // EasyLoadDenseProblem has no exact equivalent in the CLP library.  It is
@@ -369,10 +1533,49 @@ func (s *Simplex) ObjectiveValue() float64 {
 // The arguments to EasyLoadDenseProblem are the coefficients of the objective
 // function, lower and upper bounds on each variable, and a matrix in which
 // each row is of the form {lower bound, var_1, var_2, …, var_N, upper bound}.
+// EasyLoadDenseProblem panics if the arguments are inconsistent; see
+// TryEasyLoadDenseProblem for a variant that returns an error instead.
 func (s *Simplex) EasyLoadDenseProblem(obj []float64, varBounds [][2]float64, ineqs [][]float64) {
-	// Extract the lower and upper bounds for each inequality.
+	if err := s.TryEasyLoadDenseProblem(obj, varBounds, ineqs); err != nil {
+		panic(err)
+	}
+}
+
+// TryEasyLoadDenseProblem is like EasyLoadDenseProblem but returns an error
+// instead of panicking if the arguments are inconsistent.  The error wraps
+// ErrDimensionMismatch if the inequalities differ in length or do not match
+// the objective function or variable bounds and ErrInvalidArgument if the
+// number of variables cannot be determined.  In strict mode (see SetStrict),
+// TryEasyLoadDenseProblem first checks its arguments with ValidateDense and
+// returns the resulting *ValidationError, if any.
+func (s *Simplex) TryEasyLoadDenseProblem(obj []float64, varBounds [][2]float64, ineqs [][]float64) error {
+	if s.strict {
+		if err := ValidateDense(obj, varBounds, ineqs); err != nil {
+			return err
+		}
+	}
+
+	// Determine the problem dimensions.
 	nRows := len(ineqs)
-	nCols := len(ineqs[0])
+	var nCols int
+	switch {
+	case nRows > 0:
+		nCols = len(ineqs[0])
+	case obj != nil:
+		nCols = len(obj) + 2
+	default:
+		return fmt.Errorf("%w: Simplex.EasyLoadDenseProblem given neither inequalities nor an objective function", ErrInvalidArgument)
+	}
+	if nCols < 2 {
+		return fmt.Errorf("%w: Simplex.EasyLoadDenseProblem inequality 0 lacks lower and upper bounds", ErrInvalidArgument)
+	}
+	for i, row := range ineqs {
+		if len(row) != nCols {
+			return fmt.Errorf("%w: Simplex.EasyLoadDenseProblem inequality %d has length %d vs %d", ErrDimensionMismatch, i, len(row), nCols)
+		}
+	}
+
+	// Extract the lower and upper bounds for each inequality.
 	rb := make([]Bounds, nRows)
 	for i, row := range ineqs {
 		rb[i] = Bounds{
@@ -395,6 +1598,7 @@ func (s *Simplex) EasyLoadDenseProblem(obj []float64, varBounds [][2]float64, in
 		}
 		mat.AppendColumn(col)
 	}
+	mat.SetDimensions(nRows, nCols-2)
 
 	// Convert varBounds elements from [2]float64 to Bounds.
 	var cb []Bounds
@@ -407,31 +1611,65 @@ func (s *Simplex) EasyLoadDenseProblem(obj []float64, varBounds [][2]float64, in
 	}
 
 	// Load the problem into the model.
-	s.LoadProblem(mat, cb, obj, rb, nil)
+	return s.TryLoadProblem(mat, cb, obj, rb, nil)
 }
 
 // PrimalRanging returns the increases and decreases in value of the given
 // variables that do not change the solution basis.  It panics unless all input
 // slices are of length n and returns non-0 if the solution is infeasible or
-// unbounded.
+// unbounded.  See TryPrimalRanging for a variant that returns an error
+// instead.
 func (s *Simplex) PrimalRanging(n int, which []int,
 	valueIncrease []float64, sequenceIncrease []int,
 	valueDecrease []float64, sequenceDecrease []int) int {
-	// Check expected lengths.
-	if n != len(which) {
-		panic("unexpected which array length")
+	status, err := s.primalRanging(n, which, valueIncrease, sequenceIncrease, valueDecrease, sequenceDecrease)
+	if err != nil {
+		panic(err)
 	}
-	if n != len(valueIncrease) {
-		panic("unexpected valueIncrease array length")
+	return status
+}
+
+// TryPrimalRanging is like PrimalRanging but returns an error instead of
+// panicking or returning a status code.  The error wraps ErrDimensionMismatch
+// if any input slice is not of length n, ErrNotOptimal if the solution is
+// infeasible or unbounded, and ErrException if CLP throws an exception.
+func (s *Simplex) TryPrimalRanging(n int, which []int,
+	valueIncrease []float64, sequenceIncrease []int,
+	valueDecrease []float64, sequenceDecrease []int) error {
+	status, err := s.primalRanging(n, which, valueIncrease, sequenceIncrease, valueDecrease, sequenceDecrease)
+	if err != nil {
+		return err
+	}
+	if status != 0 {
+		return fmt.Errorf("%w: Simplex.PrimalRanging returned status %d", ErrNotOptimal, status)
 	}
-	if n != len(sequenceIncrease) {
-		panic("unexpected sequenceIncrease array length")
+	return nil
+}
+
+// primalRanging implements PrimalRanging and TryPrimalRanging.  It returns
+// CLP's status code and an error if the arguments are inconsistent or CLP
+// throws an exception.
+func (s *Simplex) primalRanging(n int, which []int,
+	valueIncrease []float64, sequenceIncrease []int,
+	valueDecrease []float64, sequenceDecrease []int) (int, error) {
+	// Check expected lengths.
+	type argLen struct {
+		name string
+		len  int
 	}
-	if n != len(valueDecrease) {
-		panic("unexpected valueDecrease array length")
+	for _, arg := range []argLen{
+		{"which", len(which)},
+		{"valueIncrease", len(valueIncrease)},
+		{"sequenceIncrease", len(sequenceIncrease)},
+		{"valueDecrease", len(valueDecrease)},
+		{"sequenceDecrease", len(sequenceDecrease)},
+	} {
+		if arg.len != n {
+			return 0, fmt.Errorf("%w: Simplex.PrimalRanging unexpected %s array length %d vs %d", ErrDimensionMismatch, arg.name, arg.len, n)
+		}
 	}
-	if n != len(sequenceDecrease) {
-		panic("unexpected sequenceDecrease array length")
+	if n == 0 {
+		return 0, nil
 	}
 
 	// Convert Go ints to C ints.
@@ -443,15 +1681,19 @@ func (s *Simplex) PrimalRanging(n int, which []int,
 	copyIntsGoC(cSeqDec, sequenceDecrease)
 
 	// Invoke CLP.
+	var cErr C.clp_error
 	status := C.primal_ranging(s.model, C.int(n), &cWhich[0],
 		(*C.double)(&valueIncrease[0]), &cSeqInc[0],
-		(*C.double)(&valueDecrease[0]), &cSeqDec[0])
+		(*C.double)(&valueDecrease[0]), &cSeqDec[0], &cErr)
+	if err := cError("Simplex.PrimalRanging", &cErr); err != nil {
+		return 0, err
+	}
 
 	// Convert C ints back to Go ints.
 	copyIntsCGo(which, cWhich)
 	copyIntsCGo(sequenceIncrease, cSeqInc)
 	copyIntsCGo(sequenceDecrease, cSeqDec)
-	return int(status)
+	return int(status), nil
 }
 
 // DualRanging returns the increases and decreases in costs of the given
@@ -459,37 +1701,77 @@ func (s *Simplex) PrimalRanging(n int, which []int,
 // valueDecrease can be nil.  If both are non-nil they are filled with the new
 // values corresponding to those cost changes.  This method panics unless all
 // non-nil input slices are of length n and returns non-0 if the solution is
-// infeasible or unbounded.
+// infeasible or unbounded.  See TryDualRanging for a variant that returns an
+// error instead.
 func (s *Simplex) DualRanging(n int, which []int,
 	costIncrease []float64, sequenceIncrease []int,
 	costDecrease []float64, sequenceDecrease []int,
 	valueIncrease, valueDecrease []float64) int {
+	status, err := s.dualRanging(n, which, costIncrease, sequenceIncrease,
+		costDecrease, sequenceDecrease, valueIncrease, valueDecrease)
+	if err != nil {
+		panic(err)
+	}
+	return status
+}
+
+// TryDualRanging is like DualRanging but returns an error instead of
+// panicking or returning a status code.  The error wraps ErrDimensionMismatch
+// if any non-nil input slice is not of length n, ErrNotOptimal if the
+// solution is infeasible or unbounded, and ErrException if CLP throws an
+// exception.
+func (s *Simplex) TryDualRanging(n int, which []int,
+	costIncrease []float64, sequenceIncrease []int,
+	costDecrease []float64, sequenceDecrease []int,
+	valueIncrease, valueDecrease []float64) error {
+	status, err := s.dualRanging(n, which, costIncrease, sequenceIncrease,
+		costDecrease, sequenceDecrease, valueIncrease, valueDecrease)
+	if err != nil {
+		return err
+	}
+	if status != 0 {
+		return fmt.Errorf("%w: Simplex.DualRanging returned status %d", ErrNotOptimal, status)
+	}
+	return nil
+}
+
+// dualRanging implements DualRanging and TryDualRanging.  It returns CLP's
+// status code and an error if the arguments are inconsistent or CLP throws an
+// exception.
+func (s *Simplex) dualRanging(n int, which []int,
+	costIncrease []float64, sequenceIncrease []int,
+	costDecrease []float64, sequenceDecrease []int,
+	valueIncrease, valueDecrease []float64) (int, error) {
 	// Check expected lengths.
-	if n != len(which) {
-		panic("unexpected which array length")
+	type argLen struct {
+		name string
+		len  int
 	}
-	if n != len(costIncrease) {
-		panic("unexpected costIncrease array length")
+	args := []argLen{
+		{"which", len(which)},
+		{"costIncrease", len(costIncrease)},
+		{"sequenceIncrease", len(sequenceIncrease)},
+		{"costDecrease", len(costDecrease)},
+		{"sequenceDecrease", len(sequenceDecrease)},
 	}
-	if n != len(sequenceIncrease) {
-		panic("unexpected sequenceIncrease array length")
+	useValues := valueIncrease != nil && valueDecrease != nil
+	if useValues {
+		args = append(args,
+			argLen{"valueIncrease", len(valueIncrease)},
+			argLen{"valueDecrease", len(valueDecrease)})
 	}
-	if n != len(costDecrease) {
-		panic("unexpected costDecrease array length")
+	for _, arg := range args {
+		if arg.len != n {
+			return 0, fmt.Errorf("%w: Simplex.DualRanging unexpected %s array length %d vs %d", ErrDimensionMismatch, arg.name, arg.len, n)
+		}
 	}
-	if n != len(sequenceDecrease) {
-		panic("unexpected sequenceDecrease array length")
+	if n == 0 {
+		return 0, nil
 	}
 
 	// These parameters are only used if both are non-nil.
 	var cValInc, cValDec *C.double
-	if valueIncrease != nil && valueDecrease != nil {
-		if n != len(valueIncrease) {
-			panic("unexpected valueDecrease array length")
-		}
-		if n != len(valueDecrease) {
-			panic("unexpected valueDecrease array length")
-		}
+	if useValues {
 		cValInc = (*C.double)(&valueIncrease[0])
 		cValDec = (*C.double)(&valueDecrease[0])
 	}
@@ -503,14 +1785,18 @@ func (s *Simplex) DualRanging(n int, which []int,
 	copyIntsGoC(cSeqDec, sequenceDecrease)
 
 	// Invoke CLP.
+	var cErr C.clp_error
 	status := C.dual_ranging(s.model, C.int(n), &cWhich[0],
 		(*C.double)(&costIncrease[0]), &cSeqInc[0],
 		(*C.double)(&costDecrease[0]), &cSeqDec[0],
-		cValInc, cValDec)
+		cValInc, cValDec, &cErr)
+	if err := cError("Simplex.DualRanging", &cErr); err != nil {
+		return 0, err
+	}
 
 	// Convert C ints back to Go ints.
 	copyIntsCGo(which, cWhich)
 	copyIntsCGo(sequenceIncrease, cSeqInc)
 	copyIntsCGo(sequenceDecrease, cSeqDec)
-	return int(status)
+	return int(status), nil
 }
@@ -4,10 +4,13 @@
 package clp_test
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math"
 	"os"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/lanl/clp"
@@ -47,6 +50,103 @@ func TestLoadProblem(t *testing.T) {
 	s.LoadProblem(m, nil, nil, nil, nil)
 }
 
+// cscMatrix is a pure-Go implementation of the Matrix interface.  To
+// exercise LoadProblem's gap handling, it leaves an unused element before
+// each column.
+type cscMatrix struct {
+	nr       int
+	starts   []int
+	lengths  []int
+	indices  []int
+	elements []float64
+}
+
+func (m *cscMatrix) AppendColumn(col []clp.Nonzero) {
+	m.indices = append(m.indices, -1)
+	m.elements = append(m.elements, math.NaN())
+	m.starts = append(m.starts, len(m.indices))
+	m.lengths = append(m.lengths, len(col))
+	for _, nz := range col {
+		if nz.Index >= m.nr {
+			m.nr = nz.Index + 1
+		}
+		m.indices = append(m.indices, nz.Index)
+		m.elements = append(m.elements, nz.Value)
+	}
+}
+
+func (m *cscMatrix) Dims() (rows, cols int) { return m.nr, len(m.starts) }
+
+func (m *cscMatrix) SparseData() (starts, lengths, indices []int, elements []float64) {
+	return m.starts, m.lengths, m.indices, m.elements
+}
+
+// Test if we can load a problem from a Matrix other than a PackedMatrix.
+func TestLoadGenericMatrix(t *testing.T) {
+	// Load the same problem from a PackedMatrix and a cscMatrix.
+	cols := [][]clp.Nonzero{
+		{{Index: 0, Value: 1.0}, {Index: 1, Value: 3.0}},
+		{{Index: 0, Value: 1.0}, {Index: 1, Value: -1.0}},
+	}
+	pm := clp.NewPackedMatrix()
+	gm := &cscMatrix{}
+	for _, col := range cols {
+		pm.AppendColumn(col)
+		gm.AppendColumn(col)
+	}
+	rb := []clp.Bounds{{Lower: 4, Upper: 9}, {Lower: -5, Upper: 3}}
+	obj := []float64{1.0, 2.0}
+	simp := clp.NewSimplex()
+	simp.SetStrict(true)
+	if err := simp.TryLoadProblem(gm, nil, obj, rb, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The model's matrix should match the PackedMatrix.
+	exp := pm.DenseData()
+	act := simp.Matrix().DenseData()
+	for r, row := range exp {
+		for c, v := range row {
+			if act[r][c] != v {
+				t.Fatalf("Expected %v but saw %v", exp, act)
+			}
+		}
+	}
+
+	// The model should solve as usual.
+	simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+	if v := simp.ObjectiveValue(); !closeTo(v, 6.25, 0.005) {
+		t.Fatalf("Expected 6.25 but observed %.10g", v)
+	}
+
+	// Out-of-range row indices should be rejected.
+	gm.nr = 1
+	err := clp.NewSimplex().TryLoadProblem(gm, nil, obj, nil, nil)
+	if !errors.Is(err, clp.ErrIndexOutOfRange) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIndexOutOfRange, err)
+	}
+}
+
+// Test if we can load a problem from a row-ordered matrix.
+func TestLoadRowOrdered(t *testing.T) {
+	// Minimize a + 2b subject to {4 ≤ a + b ≤ 9, -5 ≤ 3a − b ≤ 3}, as in
+	// TestPrimalSolve, but build the matrix one row at a time.
+	mat := clp.NewPackedMatrix()
+	mat.ReverseOrdering()
+	mat.AppendRow([]clp.Nonzero{{Index: 0, Value: 1.0}, {Index: 1, Value: 1.0}})
+	mat.AppendRow([]clp.Nonzero{{Index: 0, Value: 3.0}, {Index: 1, Value: -1.0}})
+	rb := []clp.Bounds{{Lower: 4, Upper: 9}, {Lower: -5, Upper: 3}}
+	simp := clp.NewSimplex()
+	simp.SetStrict(true)
+	if err := simp.TryLoadProblem(mat, nil, []float64{1.0, 2.0}, rb, nil); err != nil {
+		t.Fatal(err)
+	}
+	simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+	if v := simp.ObjectiveValue(); !closeTo(v, 6.25, 0.005) {
+		t.Fatalf("Expected 6.25 but observed %.10g", v)
+	}
+}
+
 // closeTo says if two floating-point numbers are equal within some tolerance.
 func closeTo(a, b, tol float64) bool {
 	return math.Abs(a-b) <= tol
@@ -404,3 +504,619 @@ func TestWriteMPS(t *testing.T) {
 		t.Fatalf("Failed to write a simplex model to %s", mpsName)
 	}
 }
+
+// smallMPS is an MPS representation of the problem, minimize a + 2b + 3
+// subject to {4 ≤ a + b ≤ 9, -5 ≤ 3a − b ≤ 3, a ≤ 4}.
+const smallMPS = `NAME          SMALL
+ROWS
+ N  COST
+ L  LIM1
+ G  LIM2
+COLUMNS
+    A         COST             1.0   LIM1             1.0
+    A         LIM2             3.0
+    B         COST             2.0   LIM1             1.0
+    B         LIM2            -1.0
+RHS
+    RHS       LIM1             9.0   LIM2            -5.0
+    RHS       COST            -3.0
+RANGES
+    RNG       LIM1             5.0   LIM2             8.0
+BOUNDS
+ UP BND       A                4.0
+ENDATA
+`
+
+// writeTempFile writes a string to a new temporary file and returns the
+// file's name.  The caller is responsible for removing the file.
+func writeTempFile(t *testing.T, pattern, contents string) string {
+	t.Helper()
+	f, err := ioutil.TempFile("", pattern)
+	if err != nil {
+		t.Fatalf("Failed to create a temporary file (%v)", err)
+	}
+	fName := f.Name()
+	_, err = f.WriteString(contents)
+	f.Close()
+	if err != nil {
+		os.Remove(fName)
+		t.Fatalf("Failed to write %s (%v)", fName, err)
+	}
+	return fName
+}
+
+// Test if we can read an optimization problem from an MPS file.
+func TestReadMPS(t *testing.T) {
+	// Write the problem to a temporary file.
+	mpsName := writeTempFile(t, "clp-*.mps", smallMPS)
+	defer os.Remove(mpsName)
+
+	// Read the problem back in and solve it.
+	simp := clp.NewSimplex()
+	if err := simp.ReadMPS(mpsName); err != nil {
+		t.Fatal(err)
+	}
+	if nr, nc := simp.Dims(); nr != 2 || nc != 2 {
+		t.Fatalf("Expected a 2x2 model but saw %dx%d", nr, nc)
+	}
+	simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+	v := simp.ObjectiveValue()
+	soln := simp.PrimalColumnSolution()
+
+	// Check the results.
+	if !closeTo(soln[0], 1.75, 0.005) || !closeTo(soln[1], 2.25, 0.005) {
+		t.Fatalf("Expected [1.75 2.25] but observed %v", soln)
+	}
+	if !closeTo(v, 9.25, 0.005) {
+		t.Fatalf("Expected 9.25 but observed %.10g", v)
+	}
+}
+
+// Test that reading a nonexistent MPS file returns an error.
+func TestReadMPSMissing(t *testing.T) {
+	simp := clp.NewSimplex()
+	if err := simp.ReadMPS("/nonexistent/clp-test.mps"); err == nil {
+		t.Fatal("Expected an error when reading a nonexistent MPS file")
+	}
+}
+
+// Test if row and column names pass from a matrix to a model and can be
+// queried and modified there.
+func TestNames(t *testing.T) {
+	// Set up the following problem: Minimize a + 2b subject to {4 ≤ a + b
+	// ≤ 9, -5 ≤ 3a − b ≤ 3}.
+	mat := clp.NewPackedMatrix()
+	mat.AppendColumn([]clp.Nonzero{
+		{Index: 0, Value: 1.0}, // a
+		{Index: 1, Value: 3.0}, // 3a
+	})
+	mat.AppendColumn([]clp.Nonzero{
+		{Index: 0, Value: 1.0},  // b
+		{Index: 1, Value: -1.0}, // -b
+	})
+	mat.SetColumnNames([]string{"a", "b"})
+	mat.SetRowName(0, "sum")
+	rb := []clp.Bounds{
+		{Lower: 4, Upper: 9},  // [4, 9]
+		{Lower: -5, Upper: 3}, // [-5, 3]
+	}
+	simp := clp.NewSimplex()
+	simp.LoadProblem(mat, nil, []float64{1.0, 2.0}, rb, nil)
+
+	// Check the names CLP received or made up.
+	if names := simp.ColumnNames(); len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Fatalf("Expected column names [a b] but saw %q", names)
+	}
+	if names := simp.RowNames(); len(names) != 2 || names[0] != "sum" || names[1] != "R0000001" {
+		t.Fatalf("Expected row names [sum R0000001] but saw %q", names)
+	}
+
+	// Rename a row and look it up by name.
+	if err := simp.SetRowName(1, "diff"); err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := simp.RowNameMap()["diff"]; !ok || r != 1 {
+		t.Fatalf("Expected row \"diff\" to be row 1 but saw %d (%v)", r, ok)
+	}
+	if c, ok := simp.ColumnNameMap()["b"]; !ok || c != 1 {
+		t.Fatalf("Expected column \"b\" to be column 1 but saw %d (%v)", c, ok)
+	}
+	if err := simp.SetColumnNames([]string{"x"}); err == nil {
+		t.Fatal("Expected an error when assigning too few column names")
+	}
+
+	// Ensure that out-of-range indices are rejected without reaching CLP.
+	if err := simp.SetRowName(2, "extra"); !errors.Is(err, clp.ErrIndexOutOfRange) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIndexOutOfRange, err)
+	}
+	if err := simp.SetColumnName(-1, "neg"); !errors.Is(err, clp.ErrIndexOutOfRange) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIndexOutOfRange, err)
+	}
+	if _, err := simp.RowName(-1); !errors.Is(err, clp.ErrIndexOutOfRange) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIndexOutOfRange, err)
+	}
+	if _, err := simp.ColumnName(2); !errors.Is(err, clp.ErrIndexOutOfRange) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIndexOutOfRange, err)
+	}
+	if names := simp.RowNames(); len(names) != 2 {
+		t.Fatalf("Expected 2 row names after rejected renames but saw %q", names)
+	}
+
+	// Error messages identify rows and columns by name.
+	err := simp.ModifyCoefficients([]int{1}, []int{5}, []float64{1})
+	if !errors.Is(err, clp.ErrIndexOutOfRange) || !strings.Contains(err.Error(), "row 1 (diff)") {
+		t.Fatalf("Expected an error naming row \"diff\" but saw %v", err)
+	}
+
+	// Sequence numbers cover columns then rows.
+	for seq, name := range []string{"a", "b", "sum", "diff"} {
+		if sn := simp.SequenceName(seq); sn != name {
+			t.Fatalf("Expected sequence %d to be named %q but saw %q", seq, name, sn)
+		}
+	}
+
+	// Ensure that the names survive a trip through an MPS file.
+	mps, err := ioutil.TempFile("", "clp-*.mps")
+	if err != nil {
+		t.Fatalf("Failed to create a temporary MPS file (%v)", err)
+	}
+	mpsName := mps.Name()
+	mps.Close()
+	defer os.Remove(mpsName)
+	if !simp.WriteMPS(mpsName) {
+		t.Fatalf("Failed to write a simplex model to %s", mpsName)
+	}
+	simp2 := clp.NewSimplex()
+	if err := simp2.ReadMPS(mpsName); err != nil {
+		t.Fatal(err)
+	}
+	if names := simp2.RowNames(); len(names) != 2 || names[0] != "sum" || names[1] != "diff" {
+		t.Fatalf("Expected row names [sum diff] but saw %q", names)
+	}
+}
+
+// smallProblem returns a simplex model loaded with the problem from
+// TestPrimalSolve: Minimize a + 2b subject to {4 ≤ a + b ≤ 9, -5 ≤ 3a − b ≤
+// 3}.  The model has already been solved once.
+func smallProblem() *clp.Simplex {
+	mat := clp.NewPackedMatrix()
+	mat.AppendColumn([]clp.Nonzero{
+		{Index: 0, Value: 1.0}, // a
+		{Index: 1, Value: 3.0}, // 3a
+	})
+	mat.AppendColumn([]clp.Nonzero{
+		{Index: 0, Value: 1.0},  // b
+		{Index: 1, Value: -1.0}, // -b
+	})
+	rb := []clp.Bounds{
+		{Lower: 4, Upper: 9},  // [4, 9]
+		{Lower: -5, Upper: 3}, // [-5, 3]
+	}
+	obj := []float64{1.0, 2.0} // a + 2b
+	simp := clp.NewSimplex()
+	simp.LoadProblem(mat, nil, obj, rb, nil)
+	simp.SetOptimizationDirection(clp.Minimize)
+	simp.Dual(clp.NoValuesPass, clp.NoStartFinishOptions)
+	return simp
+}
+
+// checkResolve warm-starts a solve of a modified model and compares the
+// result to what we expect.
+func checkResolve(t *testing.T, what string, simp *clp.Simplex, expSoln []float64, expObj float64) {
+	t.Helper()
+	if st := simp.Dual(clp.NoValuesPass, clp.KeepWorkAreas); st != clp.Optimal {
+		t.Fatalf("%s: expected status %d but saw %d", what, clp.Optimal, st)
+	}
+	soln := simp.PrimalColumnSolution()
+	if len(soln) != len(expSoln) {
+		t.Fatalf("%s: expected %v but observed %v", what, expSoln, soln)
+	}
+	for i, v := range soln {
+		if !closeTo(v, expSoln[i], 0.005) {
+			t.Fatalf("%s: expected %v but observed %v", what, expSoln, soln)
+		}
+	}
+	if v := simp.ObjectiveValue(); !closeTo(v, expObj, 0.005) {
+		t.Fatalf("%s: expected %v but observed %.10g", what, expObj, v)
+	}
+}
+
+// Test if we can add and delete rows and columns in a loaded model.
+func TestAddDelete(t *testing.T) {
+	simp := smallProblem()
+
+	// Add a cut, a ≤ 1.
+	err := simp.AddRows([]clp.Bounds{{Lower: math.Inf(-1), Upper: 1}},
+		[][]clp.Nonzero{{{Index: 0, Value: 1.0}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nr, nc := simp.Dims(); nr != 3 || nc != 2 {
+		t.Fatalf("Expected a 3x2 model but saw %dx%d", nr, nc)
+	}
+	checkResolve(t, "after AddRows", simp, []float64{1, 3}, 7)
+
+	// Add a column, c ∈ [0, 2], with coefficient 1 in the first row and -1
+	// in the objective function.
+	err = simp.AddColumns([]clp.Bounds{{Lower: 0, Upper: 2}}, []float64{-1},
+		[][]clp.Nonzero{{{Index: 0, Value: 1.0}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkResolve(t, "after AddColumns", simp, []float64{1, 1, 2}, 1)
+
+	// Delete the cut.
+	if err = simp.DeleteRows([]int{2}); err != nil {
+		t.Fatal(err)
+	}
+	checkResolve(t, "after DeleteRows", simp, []float64{1.25, 0.75, 2}, 0.75)
+
+	// Delete the new column.
+	if err = simp.DeleteColumns([]int{2}); err != nil {
+		t.Fatal(err)
+	}
+	checkResolve(t, "after DeleteColumns", simp, []float64{1.75, 2.25}, 6.25)
+
+	// Ensure that invalid arguments are rejected.
+	if err = simp.AddRows(nil, [][]clp.Nonzero{{{Index: 2, Value: 1.0}}}); err == nil {
+		t.Fatal("Expected an error when adding a row that refers to a nonexistent column")
+	}
+	if err = simp.AddColumns(nil, []float64{1, 2}, [][]clp.Nonzero{nil}); err == nil {
+		t.Fatal("Expected an error when adding a column with too many objective coefficients")
+	}
+	if err = simp.DeleteRows([]int{5}); err == nil {
+		t.Fatal("Expected an error when deleting a nonexistent row")
+	}
+	if nr, nc := simp.Dims(); nr != 2 || nc != 2 {
+		t.Fatalf("Expected a 2x2 model but saw %dx%d", nr, nc)
+	}
+}
+
+// Test if we can modify bounds, costs, and coefficients in a loaded model.
+func TestModify(t *testing.T) {
+	simp := smallProblem()
+
+	// Change the objective function to 2a + b.
+	if err := simp.SetObjective([]float64{2, 1}); err != nil {
+		t.Fatal(err)
+	}
+	checkResolve(t, "after SetObjective", simp, []float64{0, 4}, 4)
+
+	// Tighten the second row to -3 ≤ 3a − b ≤ 3.
+	if err := simp.SetRowBounds(1, clp.Bounds{Lower: -3, Upper: 3}); err != nil {
+		t.Fatal(err)
+	}
+	checkResolve(t, "after SetRowBounds", simp, []float64{0.25, 3.75}, 4.25)
+
+	// Change the second row to -3 ≤ 3a − 2b ≤ 3.
+	if err := simp.ModifyCoefficient(1, 1, -2); err != nil {
+		t.Fatal(err)
+	}
+	checkResolve(t, "after ModifyCoefficient", simp, []float64{1, 3}, 5)
+
+	// Bound a to [0, 10] and b to [0, 2].
+	err := simp.SetColumnSetBounds([]int{0, 1}, []clp.Bounds{{Lower: 0, Upper: 10}, {Lower: 0, Upper: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkResolve(t, "after SetColumnSetBounds", simp, []float64{2, 2}, 6)
+
+	// Change the objective function to 3a + b.
+	if err = simp.SetObjectiveCoefficients([]int{0}, []float64{3}); err != nil {
+		t.Fatal(err)
+	}
+	checkResolve(t, "after SetObjectiveCoefficients", simp, []float64{2, 2}, 8)
+
+	// Change the first row to 4 ≤ a + 2b ≤ 9.
+	if err = simp.ModifyCoefficients([]int{0}, []int{1}, []float64{2}); err != nil {
+		t.Fatal(err)
+	}
+	checkResolve(t, "after ModifyCoefficients", simp, []float64{0.25, 1.875}, 2.625)
+
+	// Bound b to [0, 1.5].
+	if err = simp.SetColumnBounds(1, clp.Bounds{Lower: 0, Upper: 1.5}); err != nil {
+		t.Fatal(err)
+	}
+	checkResolve(t, "after SetColumnBounds", simp, []float64{1, 1.5}, 4.5)
+
+	// Change the objective function to 3a + 7b.
+	if err = simp.SetObjectiveCoefficient(1, 7); err != nil {
+		t.Fatal(err)
+	}
+	checkResolve(t, "after SetObjectiveCoefficient", simp, []float64{1.75, 1.125}, 13.125)
+
+	// Ensure that invalid arguments are rejected.
+	if err = simp.SetObjective([]float64{1}); err == nil {
+		t.Fatal("Expected an error when assigning too short an objective function")
+	}
+	if err = simp.SetRowSetBounds([]int{0, 1}, []clp.Bounds{{}}); err == nil {
+		t.Fatal("Expected an error when assigning too few row bounds")
+	}
+	if err = simp.ModifyCoefficients([]int{2}, []int{0}, []float64{1}); err == nil {
+		t.Fatal("Expected an error when modifying a nonexistent row")
+	}
+	if err = simp.SetColumnBounds(2, clp.Bounds{}); !errors.Is(err, clp.ErrIndexOutOfRange) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIndexOutOfRange, err)
+	}
+	if err = simp.SetRowBounds(-1, clp.Bounds{}); !errors.Is(err, clp.ErrIndexOutOfRange) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIndexOutOfRange, err)
+	}
+	if err = simp.SetObjectiveCoefficient(5, 1); !errors.Is(err, clp.ErrIndexOutOfRange) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIndexOutOfRange, err)
+	}
+	if err = simp.ModifyCoefficient(0, 2, 1); !errors.Is(err, clp.ErrIndexOutOfRange) {
+		t.Fatalf("Expected %v but saw %v", clp.ErrIndexOutOfRange, err)
+	}
+}
+
+// Test if we can retrieve the model data from a simplex model.
+func TestGetters(t *testing.T) {
+	mpsName := writeTempFile(t, "clp-*.mps", smallMPS)
+	defer os.Remove(mpsName)
+	simp := clp.NewSimplex()
+	if err := simp.ReadMPS(mpsName); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the objective function.
+	if obj := simp.Objective(); len(obj) != 2 || obj[0] != 1 || obj[1] != 2 {
+		t.Fatalf("Expected objective [1 2] but saw %v", obj)
+	}
+	if off := simp.ObjectiveOffset(); off != -3 {
+		t.Fatalf("Expected objective offset -3 but saw %v", off)
+	}
+	if dir := simp.OptimizationDirection(); dir != clp.Minimize {
+		t.Fatalf("Expected optimization direction %v but saw %v", clp.Minimize, dir)
+	}
+
+	// Check the bounds.
+	cb := simp.ColumnBounds()
+	expCB := []clp.Bounds{{Lower: 0, Upper: 4}, {Lower: 0, Upper: math.Inf(1)}}
+	if len(cb) != 2 || cb[0] != expCB[0] || cb[1] != expCB[1] {
+		t.Fatalf("Expected column bounds %v but saw %v", expCB, cb)
+	}
+	rb := simp.RowBounds()
+	expRB := []clp.Bounds{{Lower: 4, Upper: 9}, {Lower: -5, Upper: 3}}
+	if len(rb) != 2 || rb[0] != expRB[0] || rb[1] != expRB[1] {
+		t.Fatalf("Expected row bounds %v but saw %v", expRB, rb)
+	}
+
+	// Check the matrix.
+	dense := simp.Matrix().DenseData()
+	expDense := [][]float64{{1, 1}, {3, -1}}
+	for r, row := range expDense {
+		for c, v := range row {
+			if dense[r][c] != v {
+				t.Fatalf("Expected matrix %v but saw %v", expDense, dense)
+			}
+		}
+	}
+
+	// Ensure that changes to the model are reflected by the getters.
+	simp.SetOptimizationDirection(clp.Maximize)
+	if dir := simp.OptimizationDirection(); dir != clp.Maximize {
+		t.Fatalf("Expected optimization direction %v but saw %v", clp.Maximize, dir)
+	}
+	simp.SetObjectiveOffset(5)
+	if off := simp.ObjectiveOffset(); off != 5 {
+		t.Fatalf("Expected objective offset 5 but saw %v", off)
+	}
+	if err := simp.SetRowBounds(1, clp.Bounds{Lower: math.Inf(-1), Upper: 0}); err != nil {
+		t.Fatal(err)
+	}
+	if rb = simp.RowBounds(); rb[1].Lower != math.Inf(-1) || rb[1].Upper != 0 {
+		t.Fatalf("Expected row bounds [-Inf, 0] but saw %v", rb[1])
+	}
+}
+
+// transportProblem returns a simplex model loaded with a transportation
+// problem large enough to require a nontrivial number of simplex iterations.
+func transportProblem() *clp.Simplex {
+	const nSup, nDem = 8, 12
+	mat := clp.NewPackedMatrix()
+	obj := make([]float64, 0, nSup*nDem)
+	for s := 0; s < nSup; s++ {
+		for d := 0; d < nDem; d++ {
+			mat.AppendColumn([]clp.Nonzero{
+				{Index: s, Value: 1.0},        // Shipped from s
+				{Index: nSup + d, Value: 1.0}, // Shipped to d
+			})
+			obj = append(obj, float64((s*7+d*13)%17+1))
+		}
+	}
+	rb := make([]clp.Bounds, nSup+nDem)
+	for s := 0; s < nSup; s++ {
+		rb[s] = clp.Bounds{Lower: math.Inf(-1), Upper: float64(40 + (s*11)%20)}
+	}
+	for d := 0; d < nDem; d++ {
+		rb[nSup+d] = clp.Bounds{Lower: float64(15 + (d*5)%10), Upper: math.Inf(1)}
+	}
+	simp := clp.NewSimplex()
+	simp.LoadProblem(mat, nil, obj, rb, nil)
+	simp.SetOptimizationDirection(clp.Minimize)
+	return simp
+}
+
+// Test if starting a values pass from a good point reduces the number of
+// iterations.
+func TestValuesPass(t *testing.T) {
+	// Solve the problem from scratch.
+	cold := transportProblem()
+	cold.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+	coldIters := cold.NumberIterations()
+	coldObj := cold.ObjectiveValue()
+
+	// Perturb the optimal point by 1% in alternating directions, keeping it
+	// within the column bounds, and compute the corresponding row
+	// activities.
+	cb := cold.ColumnBounds()
+	near := cold.PrimalColumnSolution()
+	for c, v := range near {
+		if c%2 == 0 {
+			v *= 1.01
+		} else {
+			v *= 0.99
+		}
+		near[c] = math.Max(cb[c].Lower, math.Min(cb[c].Upper, v))
+	}
+	nr, _ := cold.Dims()
+	rowAct := make([]float64, nr)
+	starts, lengths, indices, elements := cold.Matrix().SparseData()
+	for c, st := range starts {
+		for k := st; k < st+lengths[c]; k++ {
+			rowAct[indices[k]] += elements[k] * near[c]
+		}
+	}
+
+	// Solve the problem again, starting from the near-optimal point.
+	warm := transportProblem()
+	if err := warm.SetPrimalColumnSolution(near); err != nil {
+		t.Fatal(err)
+	}
+	if err := warm.SetPrimalRowSolution(rowAct); err != nil {
+		t.Fatal(err)
+	}
+	warm.Primal(clp.DoValuesPass, clp.NoStartFinishOptions)
+	warmIters := warm.NumberIterations()
+
+	// Check the results.
+	if v := warm.ObjectiveValue(); !closeTo(v, coldObj, 0.005) {
+		t.Fatalf("Expected %.10g but observed %.10g", coldObj, v)
+	}
+	if warmIters >= coldIters {
+		t.Fatalf("Expected fewer than %d iterations from a near-optimal starting point but observed %d", coldIters, warmIters)
+	}
+
+	// Ensure that the dual setters store what they are given.
+	duals := cold.DualRowSolution()
+	for i := range duals {
+		duals[i] += 1
+	}
+	if err := warm.SetDualRowSolution(duals); err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range warm.DualRowSolution() {
+		if v != duals[i] {
+			t.Fatalf("Expected row duals %v but saw %v", duals, warm.DualRowSolution())
+		}
+	}
+	rc := cold.DualColumnSolution()
+	if err := warm.SetDualColumnSolution(rc); err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range warm.DualColumnSolution() {
+		if v != rc[i] {
+			t.Fatalf("Expected reduced costs %v but saw %v", rc, warm.DualColumnSolution())
+		}
+	}
+
+	// Ensure that vectors of the wrong length are rejected.
+	if err := warm.SetPrimalColumnSolution([]float64{1, 2, 3}); err == nil {
+		t.Fatal("Expected an error when assigning too few column values")
+	}
+}
+
+// Test if we can explicitly free a simplex model, including more than once,
+// without disturbing a matrix loaded into it.
+func TestSimplexClose(t *testing.T) {
+	simp := smallProblem()
+	mat := simp.Matrix()
+	if err := simp.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := simp.Close(); err != nil {
+		t.Fatal(err)
+	}
+	runtime.GC()
+
+	// The matrix copy should remain usable after the model is gone.
+	if nr, nc := mat.Dims(); nr != 2 || nc != 2 {
+		t.Fatalf("Expected a 2x2 matrix but saw %dx%d", nr, nc)
+	}
+	simp = clp.NewSimplex()
+	simp.LoadProblem(mat, nil, []float64{1, 1}, nil, nil)
+	if err := mat.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mat.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if nr, nc := simp.Dims(); nr != 2 || nc != 2 {
+		t.Fatalf("Expected a 2x2 model but saw %dx%d", nr, nc)
+	}
+	simp.Close()
+}
+
+// largeModel returns an unsolved model with a single row and nc columns for
+// benchmarking data transfer.
+func largeModel(nc int) *clp.Simplex {
+	mat := clp.NewPackedMatrix()
+	for c := 0; c < nc; c++ {
+		mat.AppendColumn([]clp.Nonzero{{Index: 0, Value: float64(c%7 + 1)}})
+	}
+	obj := make([]float64, nc)
+	for c := range obj {
+		obj[c] = 1.0
+	}
+	simp := clp.NewSimplex()
+	simp.LoadProblem(mat, nil, obj, []clp.Bounds{{Lower: 1, Upper: 10}}, nil)
+	return simp
+}
+
+// Test if the Into variants of the solution getters agree with the
+// allocating variants and reuse the caller's buffer.
+func TestSolutionInto(t *testing.T) {
+	simp := smallProblem()
+	buf := make([]float64, 0, 10)
+	got := simp.PrimalColumnSolutionInto(buf)
+	if &got[:1][0] != &buf[:1][0] {
+		t.Fatal("Expected PrimalColumnSolutionInto to reuse its buffer")
+	}
+	for _, pair := range [...][2][]float64{
+		{simp.PrimalColumnSolution(), got},
+		{simp.DualColumnSolution(), simp.DualColumnSolutionInto(nil)},
+		{simp.PrimalRowSolution(), simp.PrimalRowSolutionInto(make([]float64, 0, 10))},
+		{simp.DualRowSolution(), simp.DualRowSolutionInto(make([]float64, 1))},
+	} {
+		if len(pair[0]) != len(pair[1]) {
+			t.Fatalf("Expected %v but saw %v", pair[0], pair[1])
+		}
+		for i := range pair[0] {
+			if pair[0][i] != pair[1][i] {
+				t.Fatalf("Expected %v but saw %v", pair[0], pair[1])
+			}
+		}
+	}
+}
+
+// Benchmark retrieving a large solution vector into a new slice.
+func BenchmarkPrimalColumnSolution(b *testing.B) {
+	simp := largeModel(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = simp.PrimalColumnSolution()
+	}
+}
+
+// Benchmark retrieving a large solution vector into a reused slice.
+func BenchmarkPrimalColumnSolutionInto(b *testing.B) {
+	simp := largeModel(100000)
+	var buf []float64
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = simp.PrimalColumnSolutionInto(buf)
+	}
+}
+
+// Benchmark loading a large problem.
+func BenchmarkLoadProblem(b *testing.B) {
+	simp := largeModel(100000)
+	mat := simp.Matrix()
+	obj := simp.Objective()
+	cb := simp.ColumnBounds()
+	rb := simp.RowBounds()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		simp.LoadProblem(mat, cb, obj, rb, nil)
+	}
+}
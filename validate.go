@@ -0,0 +1,282 @@
+// Input validation
+
+package clp
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// An IssueKind categorizes a problem found by Validate or ValidateDense.
+type IssueKind int
+
+// These constants are the possible values for an IssueKind.
+const (
+	IssueDimensionMismatch IssueKind = 0 // An argument's length does not match the matrix
+	IssueNaN                         = 1 // A value is NaN
+	IssueInfinite                    = 2 // A coefficient is infinite
+	IssueInvalidBounds               = 3 // A lower bound exceeds its upper bound or is +∞, or an upper bound is −∞
+	IssueIndexOutOfRange             = 4 // A Nonzero's Index lies outside the matrix
+	IssueDuplicateIndex              = 5 // Two Nonzeros in the same vector have the same Index
+	IssueRaggedRow                   = 6 // A row of a dense problem differs in length from the first row
+)
+
+// String returns the name of an IssueKind.
+func (ik IssueKind) String() string {
+	switch ik {
+	case IssueDimensionMismatch:
+		return "IssueDimensionMismatch"
+	case IssueNaN:
+		return "IssueNaN"
+	case IssueInfinite:
+		return "IssueInfinite"
+	case IssueInvalidBounds:
+		return "IssueInvalidBounds"
+	case IssueIndexOutOfRange:
+		return "IssueIndexOutOfRange"
+	case IssueDuplicateIndex:
+		return "IssueDuplicateIndex"
+	case IssueRaggedRow:
+		return "IssueRaggedRow"
+	default:
+		return fmt.Sprintf("IssueKind(%d)", int(ik))
+	}
+}
+
+// An Issue describes a single problem with the data for a model.
+type Issue struct {
+	Kind    IssueKind // Category of problem
+	Row     int       // Row number, or -1 if the problem does not concern a single row
+	Column  int       // Column number, or -1 if the problem does not concern a single column
+	Message string    // Description of the problem
+}
+
+// String describes an Issue, including its position.
+func (is Issue) String() string {
+	switch {
+	case is.Row >= 0 && is.Column >= 0:
+		return fmt.Sprintf("row %d, column %d: %s", is.Row, is.Column, is.Message)
+	case is.Row >= 0:
+		return fmt.Sprintf("row %d: %s", is.Row, is.Message)
+	case is.Column >= 0:
+		return fmt.Sprintf("column %d: %s", is.Column, is.Message)
+	default:
+		return is.Message
+	}
+}
+
+// A ValidationError lists every problem found by Validate or ValidateDense.
+// It wraps ErrInvalidArgument.
+type ValidationError struct {
+	Issues []Issue
+}
+
+// maxIssuesInError is the number of issues a ValidationError's message
+// describes before summarizing the rest.
+const maxIssuesInError = 10
+
+// Error describes the first few issues.
+func (ve *ValidationError) Error() string {
+	n := len(ve.Issues)
+	if n > maxIssuesInError {
+		n = maxIssuesInError
+	}
+	msgs := make([]string, n)
+	for i, is := range ve.Issues[:n] {
+		msgs[i] = is.String()
+	}
+	msg := fmt.Sprintf("clp: invalid problem: %s", strings.Join(msgs, "; "))
+	if len(ve.Issues) > n {
+		msg += fmt.Sprintf("; and %d more", len(ve.Issues)-n)
+	}
+	return msg
+}
+
+// Unwrap returns ErrInvalidArgument.
+func (ve *ValidationError) Unwrap() error {
+	return ErrInvalidArgument
+}
+
+// An issueList accumulates Issues.
+type issueList []Issue
+
+// add appends an Issue to an issueList.
+func (il *issueList) add(kind IssueKind, row, col int, format string, a ...interface{}) {
+	*il = append(*il, Issue{
+		Kind:    kind,
+		Row:     row,
+		Column:  col,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+// err returns a *ValidationError if the list is nonempty or nil otherwise.
+func (il issueList) err() error {
+	if len(il) == 0 {
+		return nil
+	}
+	return &ValidationError{Issues: il}
+}
+
+// checkLength records an issue if a non-nil argument has the wrong length.
+// It returns true if the length is acceptable.
+func (il *issueList) checkLength(what string, n, want int, isNil bool) bool {
+	if isNil || n == want {
+		return true
+	}
+	il.add(IssueDimensionMismatch, -1, -1, "%s has length %d but should have length %d", what, n, want)
+	return false
+}
+
+// checkValue records an issue if a coefficient is NaN or infinite.
+func (il *issueList) checkValue(what string, v float64, row, col int) {
+	switch {
+	case math.IsNaN(v):
+		il.add(IssueNaN, row, col, "%s is NaN", what)
+	case math.IsInf(v, 0):
+		il.add(IssueInfinite, row, col, "%s is %v", what, v)
+	}
+}
+
+// checkBounds records an issue if a pair of bounds is NaN or admits no
+// value.
+func (il *issueList) checkBounds(what string, b Bounds, row, col int) {
+	switch {
+	case math.IsNaN(b.Lower) || math.IsNaN(b.Upper):
+		il.add(IssueNaN, row, col, "%s [%v, %v] contain NaN", what, b.Lower, b.Upper)
+	case b.Lower > b.Upper:
+		il.add(IssueInvalidBounds, row, col, "%s lower bound %v exceeds upper bound %v", what, b.Lower, b.Upper)
+	case math.IsInf(b.Lower, 1) || math.IsInf(b.Upper, -1):
+		il.add(IssueInvalidBounds, row, col, "%s [%v, %v] admit no finite value", what, b.Lower, b.Upper)
+	}
+}
+
+// Validate checks the arguments to Simplex.LoadProblem for problems that CLP
+// does not detect itself and that would otherwise lead to a wrong answer or
+// a crash: slices whose lengths do not match the matrix, NaN or infinite
+// coefficients, lower bounds that exceed upper bounds, and matrix elements
+// whose indices lie outside the matrix or are repeated within a column.  It
+// returns nil if it finds no problems or a *ValidationError listing all of
+// them.  As with LoadProblem, any argument except m can be nil.
+func Validate(m Matrix, cb []Bounds, obj []float64, rb []Bounds, rowObj []float64) error {
+	var il issueList
+	nr, nc := m.Dims()
+
+	// Check the matrix.  A Matrix other than a PackedMatrix may return
+	// sparse data that disagree with its dimensions, in which case we
+	// check only the vectors that are well formed.
+	starts, lengths, indices, elements := m.SparseData()
+	if len(starts) != nc || len(lengths) != nc || len(indices) != len(elements) {
+		il.add(IssueDimensionMismatch, -1, -1, "matrix sparse data have %d starts, %d lengths, %d indices, and %d elements for %d columns",
+			len(starts), len(lengths), len(indices), len(elements), nc)
+		starts = nil
+	}
+	for c, st := range starts {
+		if st < 0 || lengths[c] < 0 || st+lengths[c] > len(indices) {
+			il.add(IssueIndexOutOfRange, -1, c, "matrix column spans [%d, %d) but there are only %d elements", st, st+lengths[c], len(indices))
+			continue
+		}
+		seen := make(map[int]bool, lengths[c])
+		for k := st; k < st+lengths[c]; k++ {
+			r := indices[k]
+			switch {
+			case r < 0 || r >= nr:
+				il.add(IssueIndexOutOfRange, -1, c, "matrix element has row index %d, which is not in [0, %d)", r, nr)
+				continue
+			case seen[r]:
+				il.add(IssueDuplicateIndex, r, c, "matrix element appears more than once")
+			}
+			seen[r] = true
+			il.checkValue("matrix element", elements[k], r, c)
+		}
+	}
+
+	// Check the column data.
+	if il.checkLength("column bounds", len(cb), nc, cb == nil) {
+		for c, b := range cb {
+			il.checkBounds("column bounds", b, -1, c)
+		}
+	}
+	if il.checkLength("objective function", len(obj), nc, obj == nil) {
+		for c, v := range obj {
+			il.checkValue("objective coefficient", v, -1, c)
+		}
+	}
+
+	// Check the row data.
+	if il.checkLength("row bounds", len(rb), nr, rb == nil) {
+		for r, b := range rb {
+			il.checkBounds("row bounds", b, r, -1)
+		}
+	}
+	if il.checkLength("row objective function", len(rowObj), nr, rowObj == nil) {
+		for r, v := range rowObj {
+			il.checkValue("row objective coefficient", v, r, -1)
+		}
+	}
+	return il.err()
+}
+
+// ValidateDense is like Validate but checks the arguments to
+// Simplex.EasyLoadDenseProblem.  Row numbers refer to ineqs, and column
+// numbers refer to variables, so column 0 is the first coefficient in each
+// inequality, not its lower bound.  In addition to the problems Validate
+// detects, ValidateDense reports inequalities whose length differs from that
+// of the first inequality.
+func ValidateDense(obj []float64, varBounds [][2]float64, ineqs [][]float64) error {
+	var il issueList
+
+	// Determine the number of variables.
+	nVars := len(obj)
+	switch {
+	case len(ineqs) > 0:
+		nVars = len(ineqs[0]) - 2
+	case obj == nil:
+		il.add(IssueDimensionMismatch, -1, -1, "neither inequalities nor an objective function were given")
+		return il.err()
+	}
+	if nVars < 0 {
+		il.add(IssueDimensionMismatch, 0, -1, "inequality has length %d, which leaves no room for bounds", nVars+2)
+		return il.err()
+	}
+
+	// Check each inequality.
+	for r, row := range ineqs {
+		if len(row) != nVars+2 {
+			il.add(IssueRaggedRow, r, -1, "inequality has length %d but should have length %d", len(row), nVars+2)
+			continue
+		}
+		for c, v := range row[1 : nVars+1] {
+			il.checkValue("coefficient", v, r, c)
+		}
+		il.checkBounds("inequality bounds", Bounds{Lower: row[0], Upper: row[nVars+1]}, r, -1)
+	}
+
+	// Check the variables.
+	if il.checkLength("variable bounds", len(varBounds), nVars, varBounds == nil) {
+		for c, vb := range varBounds {
+			il.checkBounds("variable bounds", Bounds{Lower: vb[0], Upper: vb[1]}, -1, c)
+		}
+	}
+	if il.checkLength("objective function", len(obj), nVars, obj == nil) {
+		for c, v := range obj {
+			il.checkValue("objective coefficient", v, -1, c)
+		}
+	}
+	return il.err()
+}
+
+// SetStrict enables or disables strict mode.  In strict mode, LoadProblem
+// and EasyLoadDenseProblem (and their Try variants) first check their
+// arguments with Validate or ValidateDense and reject data with any
+// problems.  Strict mode is off by default because validation takes time
+// proportional to the size of the problem.
+func (s *Simplex) SetStrict(strict bool) {
+	s.strict = strict
+}
+
+// Strict says whether strict mode is enabled.
+func (s *Simplex) Strict() bool {
+	return s.strict
+}
@@ -0,0 +1,116 @@
+// Test input validation
+
+package clp_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/lanl/clp"
+)
+
+// findIssue returns the first issue of a given kind in a *ValidationError.
+func findIssue(t *testing.T, err error, kind clp.IssueKind) clp.Issue {
+	var ve *clp.ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Expected a *clp.ValidationError but saw %v", err)
+	}
+	if !errors.Is(err, clp.ErrInvalidArgument) {
+		t.Fatalf("Expected %v to wrap %v", err, clp.ErrInvalidArgument)
+	}
+	for _, is := range ve.Issues {
+		if is.Kind == kind {
+			return is
+		}
+	}
+	t.Fatalf("Expected an issue of kind %v in %v", kind, err)
+	return clp.Issue{}
+}
+
+// Test if Validate accepts good data and pinpoints bad data.
+func TestValidate(t *testing.T) {
+	// Good data should pass.
+	mat := clp.NewPackedMatrix()
+	mat.AppendColumn([]clp.Nonzero{{Index: 0, Value: 1.0}, {Index: 1, Value: 2.0}})
+	mat.AppendColumn([]clp.Nonzero{{Index: 1, Value: 3.0}})
+	cb := []clp.Bounds{{Lower: 0, Upper: math.Inf(1)}, {Lower: -1, Upper: 1}}
+	obj := []float64{1, 2}
+	rb := []clp.Bounds{{Lower: math.Inf(-1), Upper: 4}, {Lower: 2, Upper: 2}}
+	if err := clp.Validate(mat, cb, obj, rb, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := clp.Validate(mat, nil, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bad data should be reported with positions.
+	obj[1] = math.NaN()
+	cb[0] = clp.Bounds{Lower: 5, Upper: 3}
+	err := clp.Validate(mat, cb, obj, rb[:1], nil)
+	if is := findIssue(t, err, clp.IssueNaN); is.Column != 1 || is.Row != -1 {
+		t.Fatalf("Expected a NaN in column 1 but saw %v", is)
+	}
+	if is := findIssue(t, err, clp.IssueInvalidBounds); is.Column != 0 {
+		t.Fatalf("Expected invalid bounds in column 0 but saw %v", is)
+	}
+	findIssue(t, err, clp.IssueDimensionMismatch)
+
+	// Bad matrix elements should be reported with positions.
+	bad := clp.NewPackedMatrix()
+	bad.AppendColumn([]clp.Nonzero{{Index: 0, Value: 1.0}})
+	bad.AppendColumn([]clp.Nonzero{{Index: 1, Value: 1.0}, {Index: 1, Value: math.Inf(1)}})
+	err = clp.Validate(bad, nil, nil, nil, nil)
+	if is := findIssue(t, err, clp.IssueDuplicateIndex); is.Row != 1 || is.Column != 1 {
+		t.Fatalf("Expected a duplicate at row 1, column 1 but saw %v", is)
+	}
+	if is := findIssue(t, err, clp.IssueInfinite); is.Row != 1 || is.Column != 1 {
+		t.Fatalf("Expected an infinite element at row 1, column 1 but saw %v", is)
+	}
+}
+
+// Test if ValidateDense catches ragged and NaN-containing inequalities.
+func TestValidateDense(t *testing.T) {
+	obj := []float64{1, 1}
+	ineqs := [][]float64{
+		{0, 2, 1, 10},
+		{3, -1, 2, 8},
+	}
+	if err := clp.ValidateDense(obj, nil, ineqs); err != nil {
+		t.Fatal(err)
+	}
+	ineqs = append(ineqs, []float64{0, 1, 1})
+	ineqs[0][2] = math.NaN()
+	err := clp.ValidateDense(obj, [][2]float64{{0, 1}, {1, 0}}, ineqs)
+	if is := findIssue(t, err, clp.IssueRaggedRow); is.Row != 2 {
+		t.Fatalf("Expected a ragged row 2 but saw %v", is)
+	}
+	if is := findIssue(t, err, clp.IssueNaN); is.Row != 0 || is.Column != 1 {
+		t.Fatalf("Expected a NaN at row 0, column 1 but saw %v", is)
+	}
+	if is := findIssue(t, err, clp.IssueInvalidBounds); is.Column != 1 {
+		t.Fatalf("Expected invalid bounds in column 1 but saw %v", is)
+	}
+}
+
+// Test if strict mode rejects bad data that LoadProblem would otherwise
+// accept.
+func TestStrictLoad(t *testing.T) {
+	mat := clp.NewPackedMatrix()
+	mat.AppendColumn([]clp.Nonzero{{Index: 0, Value: 1.0}})
+	obj := []float64{math.NaN()}
+	simp := clp.NewSimplex()
+	if simp.Strict() {
+		t.Fatal("Expected strict mode to be off by default")
+	}
+	if err := simp.TryLoadProblem(mat, nil, obj, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	simp.SetStrict(true)
+	err := simp.TryLoadProblem(mat, nil, obj, nil, nil)
+	if is := findIssue(t, err, clp.IssueNaN); is.Column != 0 {
+		t.Fatalf("Expected a NaN in column 0 but saw %v", is)
+	}
+	err = simp.TryEasyLoadDenseProblem([]float64{1}, nil, [][]float64{{0, 1, 1}, {0, 1}})
+	findIssue(t, err, clp.IssueRaggedRow)
+}